@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"strconv"
+)
+
+// CreateAnswer answers a question on behalf of the authenticated caller.
+// The request carries an Idempotency-Key, so retrying it never creates a duplicate.
+func (c *Client) CreateAnswer(ctx context.Context, questionID uint, text string) (*Answer, error) {
+	return decoded[Answer](ctx, c, request{method: "POST", path: questionPath(questionID) + "/answers/", header: idempotencyKey(), body: textBody{text}})
+}
+
+// DeleteAnswer deletes an answer.
+func (c *Client) DeleteAnswer(ctx context.Context, id uint, version int) error {
+	return c.do(ctx, request{method: "DELETE", path: answerPath(id), header: ifMatch(version)}, nil)
+}
+
+// RestoreAnswer brings back a deleted answer of a question which is not deleted. Only moderators and admins can restore answers.
+func (c *Client) RestoreAnswer(ctx context.Context, id uint, version int) (*Answer, error) {
+	return decoded[Answer](ctx, c, request{method: "POST", path: answerPath(id) + "/restore", header: ifMatch(version)})
+}
+
+// GetAnswer retrieves an answer.
+func (c *Client) GetAnswer(ctx context.Context, id uint) (*Answer, error) {
+	return decoded[Answer](ctx, c, request{method: "GET", path: answerPath(id)})
+}
+
+// UpdateAnswer replaces the text of an answer, keeping the previous one in its revisions.
+func (c *Client) UpdateAnswer(ctx context.Context, id uint, text string, version int) (*Answer, error) {
+	return decoded[Answer](ctx, c, request{method: "PATCH", path: answerPath(id), header: ifMatch(version), body: textBody{text}})
+}
+
+// GetAnswerRevisions retrieves the prior versions of the text of an answer.
+func (c *Client) GetAnswerRevisions(ctx context.Context, id uint) ([]Revision, error) {
+	var revisions []Revision
+	if err := c.do(ctx, request{method: "GET", path: answerPath(id) + "/revisions"}, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// VoteAnswer casts the caller's up (1) or down (-1) vote for an answer, replacing their previous vote.
+func (c *Client) VoteAnswer(ctx context.Context, id uint, value, version int) (*VoteSummary, error) {
+	return decoded[VoteSummary](ctx, c, request{method: "POST", path: answerPath(id) + "/vote", header: ifMatch(version), body: voteBody{value}})
+}
+
+// UnvoteAnswer withdraws the caller's vote for an answer.
+func (c *Client) UnvoteAnswer(ctx context.Context, id uint, version int) (*VoteSummary, error) {
+	return decoded[VoteSummary](ctx, c, request{method: "DELETE", path: answerPath(id) + "/vote", header: ifMatch(version)})
+}
+
+func answerPath(id uint) string {
+	return "/answers/" + strconv.FormatUint(uint64(id), 10)
+}
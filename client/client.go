@@ -0,0 +1,225 @@
+// Package client is a typed Go client of the Q&A API.
+//
+// Its methods mirror the operations of the API described by GET /openapi.json. Requests which fail with
+// a server error, are rate limited or collide with an attempt in progress are retried with backoff, see RetryPolicy,
+// and problems reported by the API are returned as *Error, which can be matched against the Err values with errors.Is.
+//
+// Methods taking a version send it in the If-Match header: they fail with ErrPreconditionFailed unless it is zero
+// or the current version of the question or answer they change.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy defines how requests failing with a 5xx status, a 429 status or a transport error are retried.
+// Requests carrying an Idempotency-Key are also retried on a 409 status, reported while their previous attempt
+// is still in progress. Deletions are not retried on transport errors, as the lost attempt may have deleted the resource,
+// which would make the retry fail with a not found problem.
+// The delay before each retry doubles from MinBackoff up to MaxBackoff, with a random jitter of up to a half of it,
+// and is never shorter than the Retry-After header of the failed response asks for.
+type RetryPolicy struct {
+	// MaxAttempts limits the number of attempts including the first one. 1 disables retries.
+	MaxAttempts int
+
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used by clients created without WithRetry.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, MinBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second}
+
+// Client makes requests to the Q&A API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	retry      RetryPolicy
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient makes the client send requests with httpClient instead of http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithToken makes the client authenticate requests with the bearer token. Without it requests are anonymous,
+// which is enough for reading only.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithRetry replaces DefaultRetryPolicy of the client.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// New creates a client of the API served at baseURL, like "https://qna.example.com".
+func New(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+	}
+
+	c := &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: http.DefaultClient, retry: DefaultRetryPolicy}
+	for _, option := range options {
+		option(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		return nil, fmt.Errorf("invalid retry policy: MaxAttempts must be positive")
+	}
+	return c, nil
+}
+
+// request describes a call to the API.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+}
+
+// ifMatch returns the If-Match header requiring the given version, or no header for version zero which means any version.
+func ifMatch(version int) http.Header {
+	if version == 0 {
+		return nil
+	}
+	return http.Header{"If-Match": {`"` + strconv.Itoa(version) + `"`}}
+}
+
+// idempotencyKey returns a header with a new Idempotency-Key, so that retries of a creation do not create duplicates.
+func idempotencyKey() http.Header {
+	var b [16]byte
+	rand.Read(b[:])
+	return http.Header{"Idempotency-Key": {hex.EncodeToString(b[:])}}
+}
+
+// do makes the request, retrying it according to the retry policy, and decodes the response body into out unless it is nil.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req, body)
+		var retryAfter time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || req.method == http.MethodDelete || attempt == c.retry.MaxAttempts {
+				return err
+			}
+		case !retryable(req, resp.StatusCode) || attempt == c.retry.MaxAttempts:
+			return decodeResponse(resp, out)
+		default:
+			retryAfter = retryAfterHeader(resp)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(max(c.backoff(attempt), retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.httpClient.Do(httpReq)
+}
+
+// backoff returns the delay before the retry following the given attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.retry.MinBackoff
+	for i := 1; i < attempt && delay < c.retry.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, c.retry.MaxBackoff)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + mathrand.N(delay/2+1)
+}
+
+// retryable reports whether an attempt of req which failed with status is worth repeating.
+// The API responds with 409 only to a request whose Idempotency-Key is used by another attempt in progress,
+// which is repeated to get the response of that attempt once it is done.
+func retryable(req request, status int) bool {
+	if status == http.StatusConflict {
+		return req.header.Get("Idempotency-Key") != ""
+	}
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryAfterHeader returns the delay the Retry-After header in seconds asks for, or zero if there is none.
+func retryAfterHeader(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// decodeResponse decodes a successful response into out, or the problem reported by an unsuccessful one into *Error.
+func decodeResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
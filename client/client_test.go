@@ -0,0 +1,490 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/handler"
+	"github.com/ppb03/qna-api/internal/mocks"
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSigningKey  = "test-signing-key-which-is-32-bytes-long"
+	testUserID      = "123e4567-e89b-12d3-a456-426614174000"
+	testOtherUserID = "9b2f3c1e-7d4a-4e8b-a1c2-0f5e6d7c8b9a"
+	testModeratorID = "5f0c6a2b-3e1d-4c7f-9a8b-2d4e6f8a0b1c"
+)
+
+// fastRetries keeps the tests from sleeping.
+var fastRetries = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+// newTestAPI creates the API backed by in-memory storage, wrapped into the middleware it needs,
+// and returns it along with the repository of its questions.
+func newTestAPI() (http.Handler, repository.QuestionRepository) {
+	store := repository.NewMemoryStore()
+	questions := repository.NewMemoryQuestionRepository(store)
+	answers := repository.NewMemoryAnswerRepository(store)
+	users := mocks.NewUserRepository(model.User{ID: testModeratorID, Role: model.RoleModerator})
+
+	router := handler.NewRouter(
+		service.NewQuestionService(questions, users),
+		service.NewAnswerService(answers, questions, users),
+		service.NewSearchService(repository.NewMemorySearchRepository(store)),
+	)
+	handler.RegisterOpenAPI(router)
+	idempotencyService := service.NewIdempotencyService(repository.NewMemoryIdempotencyRepository(store), time.Hour)
+	api := handler.AuthMiddleware(auth.NewVerifier([]byte(testSigningKey)))(handler.IdempotencyMiddleware(router, idempotencyService)(router))
+	return api, questions
+}
+
+func newTestServer(t *testing.T, h http.Handler) *httptest.Server {
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient creates a client of server authenticated as userID, or anonymous if it is empty.
+func newTestClient(t *testing.T, server *httptest.Server, userID string, options ...Option) *Client {
+	options = append([]Option{WithHTTPClient(server.Client()), WithRetry(fastRetries)}, options...)
+	if userID != "" {
+		token, err := auth.NewToken([]byte(testSigningKey), userID, time.Hour)
+		require.NoError(t, err)
+		options = append(options, WithToken(token))
+	}
+
+	c, err := New(server.URL, options...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://example.com", "http://"} {
+		_, err := New(baseURL)
+		assert.Error(t, err, baseURL)
+	}
+
+	_, err := New("http://localhost:8080", WithRetry(RetryPolicy{}))
+	assert.Error(t, err)
+}
+
+func TestClient_Questions(t *testing.T) {
+	api, _ := newTestAPI()
+	server := newTestServer(t, api)
+	author := newTestClient(t, server, testUserID)
+	other := newTestClient(t, server, testOtherUserID)
+	ctx := context.Background()
+
+	question, err := author.CreateQuestion(ctx, "How to test an SDK?", []string{" Go ", "testing"})
+	require.NoError(t, err)
+	assert.Equal(t, testUserID, question.AuthorID)
+	assert.Equal(t, []string{"go", "testing"}, question.Tags)
+	assert.Equal(t, 1, question.Version)
+
+	question, err = author.UpdateQuestion(ctx, question.ID, "How to test a Go SDK?", question.Version)
+	require.NoError(t, err)
+	assert.Equal(t, "How to test a Go SDK?", question.Text)
+
+	_, err = author.UpdateQuestion(ctx, question.ID, "Lost update", 1)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	_, err = other.UpdateQuestion(ctx, question.ID, "Vandalism", 0)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	revisions, err := author.GetQuestionRevisions(ctx, question.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "How to test an SDK?", revisions[0].Text)
+
+	_, err = other.VoteQuestion(ctx, question.ID, 1, 1)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	summary, err := other.VoteQuestion(ctx, question.ID, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, VoteSummary{Score: 1, Vote: 1}, *summary)
+	summary, err = other.UnvoteQuestion(ctx, question.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, VoteSummary{Score: 0, Vote: 0}, *summary)
+
+	tags, err := other.GetTags(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tags, 2)
+
+	got, err := other.GetQuestion(ctx, question.ID)
+	require.NoError(t, err)
+	assert.Equal(t, question.Text, got.Text)
+
+	require.NoError(t, author.DeleteQuestion(ctx, question.ID, got.Version))
+	_, err = author.GetQuestion(ctx, question.ID)
+	assert.ErrorIs(t, err, ErrQuestionNotFound)
+
+	_, err = author.RestoreQuestion(ctx, question.ID, 0)
+	assert.ErrorIs(t, err, ErrForbidden)
+	restored, err := newTestClient(t, server, testModeratorID).RestoreQuestion(ctx, question.ID, got.Version)
+	require.NoError(t, err)
+	assert.Equal(t, question.ID, restored.ID)
+}
+
+func TestClient_Answers(t *testing.T) {
+	api, _ := newTestAPI()
+	server := newTestServer(t, api)
+	author := newTestClient(t, server, testUserID)
+	responder := newTestClient(t, server, testOtherUserID)
+	ctx := context.Background()
+
+	question, err := author.CreateQuestion(ctx, "How to test an SDK?", nil)
+	require.NoError(t, err)
+
+	answer, err := responder.CreateAnswer(ctx, question.ID, "End to end")
+	require.NoError(t, err)
+	assert.Equal(t, question.ID, answer.QuestionID)
+	assert.Equal(t, testOtherUserID, answer.UserID)
+
+	_, err = responder.CreateAnswer(ctx, 99, "Nowhere")
+	assert.ErrorIs(t, err, ErrQuestionNotFound)
+
+	answer, err = responder.UpdateAnswer(ctx, answer.ID, "End to end, against httptest.Server", answer.Version)
+	require.NoError(t, err)
+	revisions, err := responder.GetAnswerRevisions(ctx, answer.ID)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 1)
+
+	summary, err := author.VoteAnswer(ctx, answer.ID, -1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, -1, summary.Score)
+	summary, err = author.UnvoteAnswer(ctx, answer.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.Score)
+
+	question, err = author.GetQuestion(ctx, question.ID)
+	require.NoError(t, err)
+	question, err = author.AcceptAnswer(ctx, question.ID, answer.ID, question.Version)
+	require.NoError(t, err)
+	require.NotNil(t, question.AcceptedAnswerID)
+	assert.Equal(t, answer.ID, *question.AcceptedAnswerID)
+
+	got, err := author.GetAnswer(ctx, answer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "End to end, against httptest.Server", got.Text)
+
+	result, err := author.Search(ctx, "against", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Hits)
+	assert.Equal(t, SearchHitAnswer, result.Hits[0].Type)
+
+	require.NoError(t, responder.DeleteAnswer(ctx, answer.ID, 0))
+	_, err = author.GetAnswer(ctx, answer.ID)
+	assert.ErrorIs(t, err, ErrAnswerNotFound)
+
+	restored, err := newTestClient(t, server, testModeratorID).RestoreAnswer(ctx, answer.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, answer.ID, restored.ID)
+}
+
+func TestClient_AllQuestions(t *testing.T) {
+	api, _ := newTestAPI()
+	server := newTestServer(t, api)
+	c := newTestClient(t, server, testUserID)
+	ctx := context.Background()
+
+	var created []uint
+	for i := range 5 {
+		tags := []string{"all"}
+		if i%2 == 0 {
+			tags = append(tags, "even")
+		}
+		question, err := c.CreateQuestion(ctx, "Question", tags)
+		require.NoError(t, err)
+		created = append(created, question.ID)
+	}
+
+	var listed []uint
+	for question, err := range c.AllQuestions(ctx, ListQuestionsParams{Limit: 2}) {
+		require.NoError(t, err)
+		listed = append(listed, question.ID)
+	}
+	assert.Equal(t, created, listed)
+
+	listed = nil
+	for question, err := range c.AllQuestions(ctx, ListQuestionsParams{Limit: 2, Tags: []string{"even"}}) {
+		require.NoError(t, err)
+		listed = append(listed, question.ID)
+	}
+	assert.Equal(t, []uint{created[0], created[2], created[4]}, listed)
+
+	listed = nil
+	for question := range c.AllQuestions(ctx, ListQuestionsParams{Limit: 2}) {
+		if listed = append(listed, question.ID); len(listed) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, created[:3], listed, "iteration stops when the loop breaks")
+
+	for _, err := range c.AllQuestions(ctx, ListQuestionsParams{Cursor: "garbage"}) {
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	api, _ := newTestAPI()
+	server := newTestServer(t, api)
+	ctx := context.Background()
+
+	_, err := newTestClient(t, server, "").CreateQuestion(ctx, "Anonymous question", nil)
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+	assert.NotEmpty(t, apiErr.RequestID)
+	assert.False(t, errors.Is(err, ErrInvalidToken))
+
+	_, err = newTestClient(t, server, "", WithToken("not a token")).GetQuestion(ctx, 1)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = newTestClient(t, server, testUserID).CreateQuestion(ctx, "", nil)
+	assert.ErrorIs(t, err, ErrEmptyText)
+	assert.EqualError(t, err, "Empty text (400): text cannot be empty")
+}
+
+func TestClient_ErrorWithoutProblemDetails(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream is down", http.StatusBadGateway)
+	}))
+
+	_, err := newTestClient(t, server, "", WithRetry(RetryPolicy{MaxAttempts: 1})).GetQuestion(context.Background(), 1)
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, &Error{Title: "Bad Gateway", Status: http.StatusBadGateway, Detail: "upstream is down"}, apiErr)
+	assert.False(t, errors.Is(err, ErrInternal))
+}
+
+// flaky fails the first failures requests with status, or drops their connections if status is zero,
+// optionally after serving them with next.
+type flaky struct {
+	next     http.Handler
+	failures int32
+	status   int
+	serve    bool
+	attempts atomic.Int32
+	keys     []string
+}
+
+func (f *flaky) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.keys = append(f.keys, r.Header.Get("Idempotency-Key"))
+	if f.attempts.Add(1) > f.failures {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+	if f.serve {
+		f.next.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if f.status == 0 {
+		panic(http.ErrAbortHandler)
+	}
+	w.Header().Set("Retry-After", "0")
+	w.WriteHeader(f.status)
+}
+
+func TestClient_RetriesLostCreation(t *testing.T) {
+	api, questions := newTestAPI()
+	f := &flaky{next: api, failures: 2, status: http.StatusBadGateway, serve: true}
+	c := newTestClient(t, newTestServer(t, f), testUserID)
+
+	question, err := c.CreateQuestion(context.Background(), "Created once", nil)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, f.attempts.Load())
+	assert.NotEmpty(t, f.keys[0])
+	assert.Equal(t, []string{f.keys[0], f.keys[0], f.keys[0]}, f.keys, "retries reuse the idempotency key")
+	stored, err := questions.GetAll(context.Background(), repository.QuestionQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 1, "retries replay the response instead of creating duplicates")
+	assert.Equal(t, stored[0].ID, question.ID)
+}
+
+func TestClient_RetriesRateLimited(t *testing.T) {
+	api, _ := newTestAPI()
+	f := &flaky{next: api, failures: 1, status: http.StatusTooManyRequests}
+	c := newTestClient(t, newTestServer(t, f), testUserID)
+
+	_, err := c.ListQuestions(context.Background(), ListQuestionsParams{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, f.attempts.Load())
+}
+
+func TestClient_RetriesKeyedRequestInProgress(t *testing.T) {
+	api, questions := newTestAPI()
+	f := &flaky{next: api, failures: 1, status: http.StatusConflict, serve: true}
+	c := newTestClient(t, newTestServer(t, f), testUserID)
+
+	question, err := c.CreateQuestion(context.Background(), "Created once", nil)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, f.attempts.Load())
+	stored, err := questions.GetAll(context.Background(), repository.QuestionQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, stored[0].ID, question.ID)
+}
+
+func TestClient_RetriesTransportErrors(t *testing.T) {
+	api, _ := newTestAPI()
+	f := &flaky{next: api, failures: 1}
+	c := newTestClient(t, newTestServer(t, f), testUserID)
+
+	_, err := c.ListQuestions(context.Background(), ListQuestionsParams{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, f.attempts.Load())
+}
+
+func TestClient_DoesNotRetryDeletionAfterTransportError(t *testing.T) {
+	api, questions := newTestAPI()
+	question, err := questions.Create(context.Background(), &model.Question{AuthorID: testUserID, Text: "Deleted once"})
+	require.NoError(t, err)
+	f := &flaky{next: api, failures: 1, serve: true}
+	c := newTestClient(t, newTestServer(t, f), testUserID)
+
+	err = c.DeleteQuestion(context.Background(), question.ID, 0)
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrQuestionNotFound, "the lost deletion is not mistaken for a missing question")
+	assert.EqualValues(t, 1, f.attempts.Load())
+	_, err = questions.GetByID(context.Background(), question.ID)
+	assert.ErrorIs(t, err, repository.ErrQuestionNotFound)
+}
+
+func TestClient_GivesUpAfterMaxAttempts(t *testing.T) {
+	api, _ := newTestAPI()
+	f := &flaky{next: api, failures: 5, status: http.StatusServiceUnavailable}
+	c := newTestClient(t, newTestServer(t, f), testUserID)
+
+	_, err := c.GetTags(context.Background())
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Status)
+	assert.EqualValues(t, fastRetries.MaxAttempts, f.attempts.Load())
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	api, _ := newTestAPI()
+	f := &flaky{next: api}
+	c := newTestClient(t, newTestServer(t, f), testUserID)
+
+	_, err := c.GetQuestion(context.Background(), 99)
+	assert.ErrorIs(t, err, ErrQuestionNotFound)
+	assert.EqualValues(t, 1, f.attempts.Load())
+}
+
+func TestClient_StopsRetryingWhenContextIsDone(t *testing.T) {
+	api, _ := newTestAPI()
+	f := &flaky{next: api, failures: 5, status: http.StatusServiceUnavailable}
+	c := newTestClient(t, newTestServer(t, f), testUserID, WithRetry(RetryPolicy{MaxAttempts: 5, MinBackoff: time.Hour, MaxBackoff: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.GetTags(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualValues(t, 1, f.attempts.Load())
+}
+
+// TestTypes_MatchOpenAPI makes sure the types of the client have the properties the server documents.
+func TestTypes_MatchOpenAPI(t *testing.T) {
+	api, _ := newTestAPI()
+	server := newTestServer(t, api)
+
+	resp, err := server.Client().Get(server.URL + "/openapi.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var document struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]any `json:"properties"`
+				Required   []string       `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&document))
+
+	for name, v := range map[string]any{
+		"Question": Question{}, "Answer": Answer{}, "Revision": Revision{}, "DiffOp": DiffOp{}, "VoteSummary": VoteSummary{},
+		"TagCount": TagCount{}, "QuestionPage": QuestionPage{}, "SearchResult": SearchResult{}, "SearchHit": SearchHit{}, "Problem": Error{},
+	} {
+		schema, ok := document.Components.Schemas[name]
+		require.True(t, ok, name)
+
+		var properties, required []string
+		typ := reflect.TypeOf(v)
+		for i := range typ.NumField() {
+			property, options, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			properties = append(properties, property)
+			if options != "omitempty" {
+				required = append(required, property)
+			}
+		}
+
+		var documented []string
+		for property := range schema.Properties {
+			documented = append(documented, property)
+		}
+		assert.ElementsMatch(t, documented, properties, name)
+		slices.Sort(required)
+		slices.Sort(schema.Required)
+		assert.Equal(t, schema.Required, required, name)
+	}
+}
+
+// problemSlugs returns the slugs of the problem types declared in the Go source file at path,
+// which are the first string argument of every call or composite literal of name.
+func problemSlugs(t *testing.T, path, name string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	require.NoError(t, err)
+
+	var slugs []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		var fun ast.Expr
+		var args []ast.Expr
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			fun, args = n.Fun, n.Args
+		case *ast.CompositeLit:
+			fun, args = n.Type, n.Elts
+		default:
+			return true
+		}
+		if ident, ok := fun.(*ast.Ident); ok && ident.Name == name {
+			slug, err := strconv.Unquote(args[0].(*ast.BasicLit).Value)
+			require.NoError(t, err)
+			slugs = append(slugs, slug)
+		}
+		return true
+	})
+	require.NotEmpty(t, slugs)
+	return slugs
+}
+
+func TestErrors_CoverProblemTypesOfAPI(t *testing.T) {
+	api := problemSlugs(t, "../internal/handler/problem.go", "problemType")
+	client := problemSlugs(t, "errors.go", "problem")
+
+	assert.ElementsMatch(t, api, client, "every problem type the API reports needs an error in the client and vice versa")
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// problemTypeBase prefixes the slugs of the problem types reported by the API.
+const problemTypeBase = "urn:qna-api:problem:"
+
+// Error is a problem reported by the API as RFC 7807 problem details. Responses of other kinds, e.g. from a proxy,
+// are reported as an Error with the status code only and the beginning of the body as Detail.
+type Error struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	RequestID string `json:"request_id"`
+}
+
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("%s (%d)", e.Title, e.Status)
+	}
+	return fmt.Sprintf("%s (%d): %s", e.Title, e.Status, e.Detail)
+}
+
+// Is reports whether target is an *Error of the same problem type, so that errors.Is(err, ErrQuestionNotFound) works.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Type != "" && t.Type == e.Type
+}
+
+func problem(slug string) *Error {
+	return &Error{Type: problemTypeBase + slug}
+}
+
+// Problems reported by the API, to be matched with errors.Is.
+var (
+	ErrInvalidQuestionID   = problem("invalid-question-id")
+	ErrInvalidAnswerID     = problem("invalid-answer-id")
+	ErrMalformedBody       = problem("malformed-body")
+	ErrInvalidBody         = problem("invalid-body")
+	ErrBodyTooLarge        = problem("body-too-large")
+	ErrEmptyText           = problem("empty-text")
+	ErrInvalidUserID       = problem("invalid-user-id")
+	ErrInvalidLimit        = problem("invalid-limit")
+	ErrInvalidCursor       = problem("invalid-cursor")
+	ErrEmptyQuery          = problem("empty-query")
+	ErrInvalidVote         = problem("invalid-vote")
+	ErrAnswerNotInQuestion = problem("answer-not-in-question")
+	ErrInvalidTag          = problem("invalid-tag")
+	ErrInvalidTagMatch     = problem("invalid-tag-match")
+	ErrMultipleEntityTags  = problem("multiple-entity-tags")
+
+	ErrInvalidIdempotencyKey    = problem("invalid-idempotency-key")
+	ErrIdempotencyKeyInProgress = problem("idempotency-key-in-progress")
+	ErrIdempotencyKeyReused     = problem("idempotency-key-reused")
+
+	ErrUnauthenticated   = problem("unauthenticated")
+	ErrInvalidToken      = problem("invalid-token")
+	ErrForbidden         = problem("forbidden")
+	ErrNotQuestionAuthor = problem("not-question-author")
+
+	ErrQuestionNotFound = problem("question-not-found")
+	ErrAnswerNotFound   = problem("answer-not-found")
+
+	ErrPreconditionFailed = problem("precondition-failed")
+	ErrRateLimitExceeded  = problem("rate-limit-exceeded")
+	ErrInternal           = problem("internal")
+)
+
+// maxErrorBodyLength limits the part of a body which is not problem details kept in Error.Detail.
+const maxErrorBodyLength = 512
+
+func decodeError(resp *http.Response) error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return fmt.Errorf("failed to read error response: %w", err)
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "application/problem+json" {
+		apiErr := &Error{}
+		if err := json.Unmarshal(body, apiErr); err == nil {
+			return apiErr
+		}
+	}
+
+	detail := strings.TrimSpace(string(body))
+	if len(detail) > maxErrorBodyLength {
+		detail = detail[:maxErrorBodyLength]
+	}
+	return &Error{Title: http.StatusText(resp.StatusCode), Status: resp.StatusCode, Detail: detail}
+}
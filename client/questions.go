@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// CreateQuestion creates a question authored by the authenticated caller. Tags are normalized by the server.
+// The request carries an Idempotency-Key, so retrying it never creates a duplicate.
+func (c *Client) CreateQuestion(ctx context.Context, text string, tags []string) (*Question, error) {
+	body := struct {
+		Text string   `json:"text"`
+		Tags []string `json:"tags,omitempty"`
+	}{text, tags}
+
+	return decoded[Question](ctx, c, request{method: "POST", path: "/questions/", header: idempotencyKey(), body: body})
+}
+
+// DeleteQuestion deletes a question along with its answers.
+func (c *Client) DeleteQuestion(ctx context.Context, id uint, version int) error {
+	return c.do(ctx, request{method: "DELETE", path: questionPath(id), header: ifMatch(version)}, nil)
+}
+
+// RestoreQuestion brings back a deleted question along with its answers. Only moderators and admins can restore questions.
+func (c *Client) RestoreQuestion(ctx context.Context, id uint, version int) (*Question, error) {
+	return decoded[Question](ctx, c, request{method: "POST", path: questionPath(id) + "/restore", header: ifMatch(version)})
+}
+
+// GetQuestion retrieves a question along with its answers, the accepted one first and the rest by score.
+func (c *Client) GetQuestion(ctx context.Context, id uint) (*Question, error) {
+	return decoded[Question](ctx, c, request{method: "GET", path: questionPath(id)})
+}
+
+// UpdateQuestion replaces the text of a question, keeping the previous one in its revisions.
+func (c *Client) UpdateQuestion(ctx context.Context, id uint, text string, version int) (*Question, error) {
+	return decoded[Question](ctx, c, request{method: "PATCH", path: questionPath(id), header: ifMatch(version), body: textBody{text}})
+}
+
+// GetQuestionRevisions retrieves the prior versions of the text of a question.
+func (c *Client) GetQuestionRevisions(ctx context.Context, id uint) ([]Revision, error) {
+	var revisions []Revision
+	if err := c.do(ctx, request{method: "GET", path: questionPath(id) + "/revisions"}, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// VoteQuestion casts the caller's up (1) or down (-1) vote for a question, replacing their previous vote.
+func (c *Client) VoteQuestion(ctx context.Context, id uint, value, version int) (*VoteSummary, error) {
+	return decoded[VoteSummary](ctx, c, request{method: "POST", path: questionPath(id) + "/vote", header: ifMatch(version), body: voteBody{value}})
+}
+
+// UnvoteQuestion withdraws the caller's vote for a question.
+func (c *Client) UnvoteQuestion(ctx context.Context, id uint, version int) (*VoteSummary, error) {
+	return decoded[VoteSummary](ctx, c, request{method: "DELETE", path: questionPath(id) + "/vote", header: ifMatch(version)})
+}
+
+// AcceptAnswer marks an answer as the solution of its question. Only the author of the question can accept answers.
+func (c *Client) AcceptAnswer(ctx context.Context, id, answerID uint, version int) (*Question, error) {
+	path := fmt.Sprintf("%s/accept/%d", questionPath(id), answerID)
+	return decoded[Question](ctx, c, request{method: "POST", path: path, header: ifMatch(version)})
+}
+
+// ListQuestions retrieves a page of questions, oldest first. See AllQuestions to go through every page.
+func (c *Client) ListQuestions(ctx context.Context, params ListQuestionsParams) (*QuestionPage, error) {
+	query := url.Values{}
+	if params.Limit != 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+	if params.Match != "" {
+		query.Set("match", params.Match)
+	}
+	query["tag"] = params.Tags
+
+	return decoded[QuestionPage](ctx, c, request{method: "GET", path: "/questions/", query: query})
+}
+
+// AllQuestions iterates over the questions selected by params page by page, starting from params.Cursor.
+// Iteration stops after the first error, which is yielded along with a zero Question.
+func (c *Client) AllQuestions(ctx context.Context, params ListQuestionsParams) iter.Seq2[Question, error] {
+	return func(yield func(Question, error) bool) {
+		for {
+			page, err := c.ListQuestions(ctx, params)
+			if err != nil {
+				yield(Question{}, err)
+				return
+			}
+			for _, question := range page.Questions {
+				if !yield(question, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			params.Cursor = page.NextCursor
+		}
+	}
+}
+
+// GetTags retrieves the tags used by questions along with the number of questions labelled with each.
+func (c *Client) GetTags(ctx context.Context) ([]TagCount, error) {
+	var tags []TagCount
+	if err := c.do(ctx, request{method: "GET", path: "/tags"}, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Search retrieves questions and answers matching the query, best first. Zero limit leaves the number of hits to the server.
+func (c *Client) Search(ctx context.Context, query string, limit int) (*SearchResult, error) {
+	values := url.Values{"q": {query}}
+	if limit != 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	return decoded[SearchResult](ctx, c, request{method: "GET", path: "/search", query: values})
+}
+
+func questionPath(id uint) string {
+	return "/questions/" + strconv.FormatUint(uint64(id), 10)
+}
+
+type textBody struct {
+	Text string `json:"text"`
+}
+
+type voteBody struct {
+	Value int `json:"value"`
+}
+
+// decoded makes the request and returns its response body decoded into a new T.
+func decoded[T any](ctx context.Context, c *Client, req request) (*T, error) {
+	out := new(T)
+	if err := c.do(ctx, req, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,113 @@
+package client
+
+import "time"
+
+// Question is a question along with its answers, as returned by the API.
+// Version changes whenever the question or any of its answers changes, pass it to the methods modifying the question
+// to make them fail with ErrPreconditionFailed if someone else has changed it in the meantime.
+type Question struct {
+	ID               uint      `json:"id"`
+	AuthorID         string    `json:"author_id,omitempty"`
+	Text             string    `json:"text"`
+	Score            int       `json:"score"`
+	AcceptedAnswerID *uint     `json:"accepted_answer_id,omitempty"`
+	Version          int       `json:"version"`
+	CreatedAt        time.Time `json:"created_at"`
+	Answers          []Answer  `json:"answers,omitempty"`
+	Tags             []string  `json:"tags,omitempty"`
+}
+
+// Answer is an answer to a question, as returned by the API.
+type Answer struct {
+	ID         uint      `json:"id"`
+	QuestionID uint      `json:"question_id"`
+	UserID     string    `json:"user_id"`
+	Text       string    `json:"text"`
+	Score      int       `json:"score"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Revision is a prior version of the text of a question or an answer, with the diff to the version which replaced it.
+type Revision struct {
+	ID         uint      `json:"id"`
+	QuestionID *uint     `json:"question_id,omitempty"`
+	AnswerID   *uint     `json:"answer_id,omitempty"`
+	Text       string    `json:"text"`
+	EditorID   string    `json:"editor_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Diff       []DiffOp  `json:"diff,omitempty"`
+}
+
+// Kinds of DiffOp operations.
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffOp is a single word-level edit operation between two versions of a text.
+type DiffOp struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// VoteSummary is the score of a question or an answer after the caller voted. Vote is the caller's current vote: 1, -1 or 0.
+type VoteSummary struct {
+	Score int `json:"score"`
+	Vote  int `json:"vote"`
+}
+
+// TagCount is a tag along with the number of questions labelled with it.
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// QuestionPage is a page of questions. NextCursor is empty on the last page.
+type QuestionPage struct {
+	Questions  []Question `json:"questions"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// Semantics of filtering questions by several tags.
+const (
+	TagMatchAll = "all"
+	TagMatchAny = "any"
+)
+
+// ListQuestionsParams selects the page of questions returned by ListQuestions.
+type ListQuestionsParams struct {
+	// Limit is the maximum number of questions on the page. Zero leaves it to the server.
+	Limit int
+
+	// Cursor is the NextCursor of the previous page. Empty means the first page.
+	Cursor string
+
+	// Tags restricts the page to questions labelled with the given tags.
+	Tags []string
+
+	// Match is either TagMatchAll, the default, or TagMatchAny.
+	Match string
+}
+
+// Kinds of entities a SearchHit may refer to.
+const (
+	SearchHitQuestion = "question"
+	SearchHitAnswer   = "answer"
+)
+
+// SearchHit is a single full-text search match in a question or an answer.
+// Snippet contains the matched fragment of the text, HTML-escaped, with the matched terms wrapped in <mark> tags.
+type SearchHit struct {
+	Type       string  `json:"type"`
+	ID         uint    `json:"id"`
+	QuestionID uint    `json:"question_id"`
+	Rank       float64 `json:"rank"`
+	Snippet    string  `json:"snippet"`
+}
+
+// SearchResult is the list of search hits, best first.
+type SearchResult struct {
+	Hits []SearchHit `json:"hits"`
+}
@@ -7,48 +7,159 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/ppb03/qna-api/internal/auth"
 	"github.com/ppb03/qna-api/internal/config"
 	"github.com/ppb03/qna-api/internal/handler"
+	"github.com/ppb03/qna-api/internal/metrics"
+	"github.com/ppb03/qna-api/internal/model"
 	"github.com/ppb03/qna-api/internal/service"
+	"github.com/ppb03/qna-api/internal/tracing"
 	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/migrations"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 func main() {
-	if err := config.Load(); err != nil {
+	// Migrations need the database settings only, so the secrets of the server are not required for them.
+	migrate := len(os.Args) > 1 && os.Args[1] == "migrate"
+	load := config.Load
+	if migrate {
+		load = config.LoadForMigrations
+	}
+
+	cfg, err := load()
+	if err != nil {
 		slog.Error("failed to load config: " +  err.Error())
 		os.Exit(1)
 	}
+	slog.SetDefault(newLogger(cfg.Log))
+	slog.Info("configuration loaded", "config", cfg)
+
+	if migrate {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			slog.Error("migration failed: " + err.Error())
+			os.Exit(1)
+		}
+		return
+	}
 
-	db, err := gorm.Open(postgres.Open(config.DBDSN), &gorm.Config{})
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.File)
 	if err != nil {
-		slog.Error("failed to connect to database: " + err.Error())
+		slog.Error("failed to set up tracing: " + err.Error())
 		os.Exit(1)
 	}
 
-	questionRepository := repository.NewPostgresQuestionRepository(db)
-	answerRepository := repository.NewPostgresAnswerRepository(db)
+	var (
+		questionRepository    repository.QuestionRepository
+		answerRepository      repository.AnswerRepository
+		searchRepository      repository.SearchRepository
+		userRepository        repository.UserRepository
+		idempotencyRepository repository.IdempotencyRepository
+		rateLimitRepository   repository.RateLimitRepository
+		readinessChecks       []service.ReadinessCheck
+	)
 
-	questionService := service.NewQuestionService(questionRepository)
-	answerService := service.NewAnswerService(answerRepository, questionRepository)
+	switch cfg.Storage {
+	case config.StorageMemory:
+		slog.Warn("using in-memory storage, data will be lost on restart")
+		store := repository.NewMemoryStore()
+		questionRepository = repository.NewMemoryQuestionRepository(store)
+		answerRepository = repository.NewMemoryAnswerRepository(store)
+		searchRepository = repository.NewMemorySearchRepository(store)
+		userRepository = repository.NewMemoryUserRepository(memoryUsers(cfg.Memory)...)
+		idempotencyRepository = repository.NewMemoryIdempotencyRepository(store)
+	default:
+		db, err := openDatabase(cfg.Database)
+		if err != nil {
+			slog.Error("failed to connect to database: " + err.Error())
+			os.Exit(1)
+		}
+		if err := prepareSchema(db, cfg.Database); err != nil {
+			slog.Error("database schema is not ready: " + err.Error())
+			os.Exit(1)
+		}
+		if err := registerDBMetrics(db, cfg.Database.Driver); err != nil {
+			slog.Error("failed to register database metrics: " + err.Error())
+			os.Exit(1)
+		}
+		if readinessChecks, err = databaseChecks(db, cfg.Database.Driver); err != nil {
+			slog.Error("failed to set up readiness checks: " + err.Error())
+			os.Exit(1)
+		}
 
-	router := handler.NewRouter(questionService, answerService)
+		if cfg.Database.Driver == config.DriverSQLite {
+			questionRepository = repository.NewSQLiteQuestionRepository(db)
+			answerRepository = repository.NewSQLiteAnswerRepository(db)
+			searchRepository = repository.NewSQLiteSearchRepository(db)
+			userRepository = repository.NewSQLiteUserRepository(db)
+			idempotencyRepository = repository.NewSQLiteIdempotencyRepository(db)
+			rateLimitRepository = repository.NewSQLiteRateLimitRepository(db)
+		} else {
+			questionRepository = repository.NewPostgresQuestionRepository(db)
+			answerRepository = repository.NewPostgresAnswerRepository(db)
+			searchRepository = repository.NewPostgresSearchRepository(db)
+			userRepository = repository.NewPostgresUserRepository(db)
+			idempotencyRepository = repository.NewPostgresIdempotencyRepository(db)
+			rateLimitRepository = repository.NewPostgresRateLimitRepository(db)
+		}
+	}
+	// Buckets go to the database only when limits have to hold across replicas, as it costs a write per limited request.
+	if cfg.RateLimit.Store == config.RateLimitStoreMemory {
+		rateLimitRepository = repository.NewMemoryRateLimitRepository()
+	}
+
+	questionService := service.NewTracedQuestionService(service.NewQuestionService(questionRepository, userRepository))
+	answerService := service.NewTracedAnswerService(service.NewAnswerService(answerRepository, questionRepository, userRepository))
+	searchService := service.NewTracedSearchService(service.NewSearchService(searchRepository))
+	purgeService := service.NewTracedPurgeService(service.NewPurgeService(questionRepository, answerRepository, idempotencyRepository, rateLimitRepository, cfg.Purge.Retention))
+	idempotencyService := service.NewTracedIdempotencyService(service.NewIdempotencyService(idempotencyRepository, cfg.Idempotency.TTL))
+	rateLimitService := service.NewTracedRateLimitService(service.NewRateLimitService(rateLimitRepository))
+	healthService := service.NewHealthService(cfg.Server.ReadinessTimeout, readinessChecks...)
+
+	router := handler.NewRouter(questionService, answerService, searchService)
+	handler.RegisterHealth(router, healthService)
+	handler.RegisterMetrics(router)
+	handler.RegisterOpenAPI(router)
+	authMiddleware := handler.AuthMiddleware(auth.NewVerifier([]byte(cfg.Auth.SigningKey)))
+
+	var api http.Handler = handler.IdempotencyMiddleware(router, idempotencyService)(router)
+	if cfg.Server.ValidateRequests {
+		api = handler.ValidationMiddleware(router)(api)
+	}
+	if cfg.RateLimit.Enabled {
+		api = handler.RateLimitMiddleware(router, rateLimitService, rateLimits(cfg.RateLimit.Routes))(api)
+	}
+
+	// Tracing goes first so that the logger made by RequestIDMiddleware can refer to the trace,
+	// and logging goes before authentication so that rejected requests are logged too.
+	// Rate limiting follows authentication to tell users apart, and precedes idempotency so that replays count too.
+	// Invalid bodies are rejected before idempotency keys are taken, so that a corrected request can reuse the key.
+	chain := handler.TracingMiddleware(router)(
+		handler.MetricsMiddleware(router)(
+			handler.RequestIDMiddleware(
+				handler.LoggingMiddleware(
+					authMiddleware(api),
+				),
+			),
+		),
+	)
 
-	port := config.ServerPort
 	server := &http.Server{
-		Addr:         ":" + port,
-		Handler:      handler.LoggingMiddleware(router),
-		ReadTimeout:  8 * time.Second,
-		WriteTimeout: 16 * time.Second,
-		IdleTimeout:  16 * time.Second,
+		Addr:         ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:      chain,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 	
-	slog.Info("server is starting on port :" + port)
+	slog.Info("server is starting on port " + server.Addr)
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			slog.Error("server failed to start: " + err.Error())
@@ -56,20 +167,135 @@ func main() {
 		}
 	}()
 	
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	go runPurge(purgeCtx, purgeService, cfg.Purge.Interval)
+
 	// Graceful shutdown for HTTP-server
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
 	<-done
 	slog.Info("server shutting down gracefully")
+	stopPurge()
+
+	// Failing readiness probes for a while lets load balancers stop routing new requests before the listener closes.
+	healthService.Drain()
+	time.Sleep(cfg.Server.ShutdownDelay)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		slog.Error("server shutdown failed", "error", err)
 		os.Exit(1)
 	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
 
 	slog.Info("server stopped")
-}
\ No newline at end of file
+}
+
+// databaseChecks creates readiness checks which ping db and make sure its schema is not behind the binary.
+func databaseChecks(db *gorm.DB, driver string) ([]service.ReadinessCheck, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	provider, err := newMigrationProvider(db, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return []service.ReadinessCheck{
+		{Name: "database", Check: sqlDB.PingContext},
+		{Name: "migrations", Check: func(ctx context.Context) error { return migrations.Check(ctx, provider) }},
+	}, nil
+}
+
+// registerDBMetrics exposes the connection pool statistics of db.
+func registerDBMetrics(db *gorm.DB, driver string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return metrics.RegisterDB(sqlDB, driver)
+}
+
+// openDatabase connects to the database selected by cfg.Driver and sizes its connection pool.
+// Every query is traced as a child of the span carried by the context passed to WithContext.
+func openDatabase(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	// PostgreSQL keeps timestamps with microsecond precision, so they are truncated beforehand
+	// for the values returned on creation to match the ones read back later.
+	dialector, gormConfig := postgres.Open(cfg.DSN()), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC().Truncate(time.Microsecond) },
+	}
+	if cfg.Driver == config.DriverSQLite {
+		// SQLite compares timestamps as text, so they are all kept in UTC.
+		dialector, gormConfig.NowFunc = sqlite.Open(cfg.DSN()), func() time.Time { return time.Now().UTC() }
+	}
+
+	db, err := gorm.Open(dialector, gormConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(repository.NewTracingPlugin()); err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	return db, nil
+}
+
+// rateLimits converts the configured limits of routes to the ones RateLimitMiddleware takes.
+func rateLimits(routes map[string]config.RouteLimit) map[string]model.RateLimit {
+	limits := make(map[string]model.RateLimit, len(routes))
+	for pattern, route := range routes {
+		limits[pattern] = model.RateLimit{Requests: route.Requests, Period: route.Period, Burst: route.Burst}
+	}
+	return limits
+}
+
+// memoryUsers lists the users having a role other than model.RoleUser in in-memory storage.
+func memoryUsers(cfg config.MemoryConfig) []model.User {
+	var users []model.User
+	for _, id := range cfg.Moderators {
+		users = append(users, model.User{ID: id, Role: model.RoleModerator})
+	}
+	for _, id := range cfg.Admins {
+		users = append(users, model.User{ID: id, Role: model.RoleAdmin})
+	}
+	return users
+}
+
+// newLogger creates the application logger writing to stderr in the format and at the level set by cfg.
+func newLogger(cfg config.LogConfig) *slog.Logger {
+	options := &slog.HandlerOptions{Level: cfg.Level}
+	if cfg.Format == config.LogFormatJSON {
+		return slog.New(slog.NewJSONHandler(os.Stderr, options))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, options))
+}
+
+// runPurge periodically removes expired soft-deleted records until ctx is cancelled.
+func runPurge(ctx context.Context, purgeService service.PurgeService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := purgeService.Purge(ctx); err != nil {
+				slog.Error("purge of soft-deleted records failed", "error", err)
+			}
+		}
+	}
+}
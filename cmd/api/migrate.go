@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/config"
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/migrations"
+
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: qna-api migrate up|down|status|check"
+
+// runMigrate implements the migrate subcommand: up applies all pending migrations,
+// down rolls back the last one, status lists the migrations with the time they were applied
+// and check prints the differences between the model structs and the schema as JSON.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+	if cfg.Storage != config.StorageDatabase {
+		return fmt.Errorf("migrations require STORAGE=%s", config.StorageDatabase)
+	}
+
+	db, err := openDatabase(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	provider, err := newMigrationProvider(db, cfg.Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		results, err := provider.Up(ctx)
+		if err != nil {
+			return err
+		}
+		logMigrations(results)
+		if len(results) == 0 {
+			slog.Info("no pending migrations")
+		}
+	case "down":
+		result, err := provider.Down(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			slog.Info("no migrations to roll back")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logMigrations([]*goose.MigrationResult{result})
+	case "status":
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "MIGRATION\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.State == goose.StateApplied {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\n", status.Source.Path, appliedAt)
+		}
+		return w.Flush()
+	case "check":
+		drifts, err := repository.CheckSchema(ctx, db)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]any{"drifts": drifts}); err != nil {
+			return err
+		}
+		if len(drifts) > 0 {
+			return fmt.Errorf("schema has drifted from the models in %d places", len(drifts))
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
+
+// prepareSchema applies pending migrations if cfg.MigrateOnStart is set,
+// makes sure the schema is not behind the version the binary expects and logs any drift from the models.
+func prepareSchema(db *gorm.DB, cfg config.DatabaseConfig) error {
+	provider, err := newMigrationProvider(db, cfg.Driver)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if cfg.MigrateOnStart {
+		results, err := provider.Up(ctx)
+		if err != nil {
+			return err
+		}
+		logMigrations(results)
+	}
+
+	if err := migrations.Check(ctx, provider); err != nil {
+		return fmt.Errorf("%w, run \"qna-api migrate up\" or set MIGRATE_ON_START=true", err)
+	}
+
+	// Drift does not prevent the start since the gorm repositories may not touch the affected columns.
+	drifts, err := repository.CheckSchema(ctx, db)
+	if err != nil {
+		slog.Warn("failed to check the schema for drift", "error", err)
+	}
+	for _, drift := range drifts {
+		slog.Warn("schema drift: " + drift.String())
+	}
+	return nil
+}
+
+func newMigrationProvider(db *gorm.DB, driver string) (*goose.Provider, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrations.NewProvider(sqlDB, driver)
+}
+
+func logMigrations(results []*goose.MigrationResult) {
+	for _, result := range results {
+		slog.Info("migration completed", "migration", result.Source.Path, "direction", result.Direction, "duration", result.Duration)
+	}
+}
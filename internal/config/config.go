@@ -1,42 +1,644 @@
 // Package config provides configuration management for the application.
-// It handles loading environment variables and setting up application configuration.
+// The configuration is read from an optional YAML file and environment variables, which take precedence over the file.
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Storage backends selectable with the STORAGE environment variable.
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+
+	// StoragePostgres is the name StorageDatabase had before the SQLite driver was added.
+	// Load still accepts it and replaces it with StorageDatabase.
+	//
+	// Deprecated: use StorageDatabase.
+	StoragePostgres = "postgres"
+)
+
+// Database drivers selectable with the DB_DRIVER environment variable.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Rate limit stores selectable with the RATE_LIMIT_STORE environment variable.
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStoreDatabase = "database"
+)
+
+// Trace exporters selectable with the TRACING_EXPORTER environment variable.
+const (
+	TracingNone   = "none"
+	TracingOTLP   = "otlp"
+	TracingStdout = "stdout"
+	TracingFile   = "file"
 )
 
-// ServerPort defines the default port for the HTTP server.
-const ServerPort = "8080"
-
-// DBDSN holds the database connection string constructed from environment variables.
-var DBDSN string
-
-// Load initializes the application configuration by reading environment variables
-// and constructing the database connection string. Uses default values for missing variables.
-func Load() error {
-	dbHost := getEnv("DB_HOST", "db")
-	dbPort := getEnv("DB_PORT", "5432")
-	dbUser := getEnv("DB_USER", "postgres")
-	dbPassword := getEnv("DB_PASSWORD", "password")
-	dbName := getEnv("DB_NAME", "qna_db")
-	dbSSLMode := getEnv("DB_SSLMODE", "disable")
-
-	DBDSN = fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		dbHost, dbPort, dbUser, dbPassword, dbName, dbSSLMode,
+// Log formats selectable with the LOG_FORMAT environment variable.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// minSigningKeyLength is the minimal length of AuthConfig.SigningKey in bytes.
+const minSigningKeyLength = 32
+
+// Config holds the configuration of the application. Its zero value is not valid, use Load or Default.
+type Config struct {
+	Server ServerConfig `yaml:"server"`
+
+	// Storage defines where questions and answers are kept: StorageDatabase or StorageMemory.
+	// Data kept in memory is lost on restart.
+	Storage string `yaml:"storage"`
+
+	Database    DatabaseConfig    `yaml:"database"`
+	Memory      MemoryConfig      `yaml:"memory"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Purge       PurgeConfig       `yaml:"purge"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Log         LogConfig         `yaml:"log"`
+}
+
+// ServerConfig holds the settings of the HTTP server.
+type ServerConfig struct {
+	Port         int           `yaml:"port"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+
+	// ShutdownDelay defines how long the server keeps serving with a failing readiness probe
+	// after receiving SIGTERM before it stops accepting connections.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+
+	// ShutdownTimeout limits the time in-flight requests are given to complete once the server stops accepting connections.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// ReadinessTimeout limits the time each readiness check may take.
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout"`
+
+	// ValidateRequests defines whether request bodies are checked against the OpenAPI document before reaching handlers.
+	ValidateRequests bool `yaml:"validate_requests"`
+}
+
+// DatabaseConfig holds the settings of the database used with StorageDatabase.
+type DatabaseConfig struct {
+	// Driver is either DriverPostgres or DriverSQLite.
+	Driver string `yaml:"driver"`
+
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password Secret `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+
+	// SQLitePath is the path of the database file used with DriverSQLite.
+	SQLitePath string `yaml:"sqlite_path"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+
+	// MigrateOnStart defines whether pending migrations are applied when the server starts.
+	// Otherwise the server refuses to start until they are applied with the migrate command.
+	MigrateOnStart bool `yaml:"migrate_on_start"`
+}
+
+// MemoryConfig holds the settings of StorageMemory.
+type MemoryConfig struct {
+	// Moderators and Admins list the IDs of the users having the moderator and the admin role,
+	// since in-memory storage has no users table to assign roles in. Everyone else is an ordinary user.
+	Moderators []string `yaml:"moderators"`
+	Admins     []string `yaml:"admins"`
+}
+
+// AuthConfig holds the settings of bearer token authentication.
+type AuthConfig struct {
+	// SigningKey is the shared HMAC key used to verify bearer tokens.
+	SigningKey Secret `yaml:"signing_key"`
+}
+
+// PurgeConfig holds the settings of the permanent removal of soft-deleted questions and answers.
+type PurgeConfig struct {
+	// Retention defines how long soft-deleted questions and answers are kept before being permanently removed.
+	Retention time.Duration `yaml:"retention"`
+
+	// Interval defines how often the purge of expired soft-deleted questions and answers runs.
+	Interval time.Duration `yaml:"interval"`
+}
+
+// IdempotencyConfig holds the settings of requests made with the Idempotency-Key header.
+type IdempotencyConfig struct {
+	// TTL defines how long the response to such a request is replayed for its retries.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// RateLimitConfig holds the limits of requests each client, identified by the authenticated user or the IP address,
+// can make to the routes of the API.
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Store defines where token buckets are kept: RateLimitStoreMemory, separately by each replica,
+	// or RateLimitStoreDatabase, so that the limits hold across replicas. The latter requires StorageDatabase.
+	Store string `yaml:"store"`
+
+	// Routes maps route patterns registered by handler.NewRouter, like "POST /questions/{id}/answers/", to their limits.
+	// Routes from the file are merged into the default ones. Routes missing from the map are not limited.
+	Routes map[string]RouteLimit `yaml:"routes"`
+}
+
+// RouteLimit allows a client Burst requests at once, replenished at the rate of Requests per Period.
+type RouteLimit struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst"`
+}
+
+// TracingConfig holds the settings of OpenTelemetry tracing.
+type TracingConfig struct {
+	// Exporter defines where spans are sent: TracingNone, TracingOTLP, TracingStdout or TracingFile.
+	Exporter string `yaml:"exporter"`
+
+	// File holds the path of the file spans are appended to with TracingFile.
+	File string `yaml:"file"`
+}
+
+// LogConfig holds the settings of the application log.
+type LogConfig struct {
+	Level slog.Level `yaml:"level"`
+
+	// Format is either LogFormatText or LogFormatJSON.
+	Format string `yaml:"format"`
+}
+
+// Secret is a string which is redacted when printed, logged or marshalled.
+type Secret string
+
+const redacted = "[REDACTED]"
+
+// String returns a placeholder instead of a set secret, so fmt does not reveal it.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// LogValue keeps the secret out of logs.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalText keeps the secret out of JSON and other text encodings.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Default returns the configuration used for settings missing from both the file and the environment.
+// The database password and the signing key have no defaults on purpose.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:             8080,
+			ReadTimeout:      8 * time.Second,
+			WriteTimeout:     16 * time.Second,
+			IdleTimeout:      16 * time.Second,
+			ShutdownDelay:    5 * time.Second,
+			ShutdownTimeout:  30 * time.Second,
+			ReadinessTimeout: 2 * time.Second,
+		},
+		Storage: StorageDatabase,
+		Database: DatabaseConfig{
+			Driver:          DriverPostgres,
+			Host:            "db",
+			Port:            5432,
+			User:            "postgres",
+			Name:            "qna_db",
+			SSLMode:         "disable",
+			SQLitePath:      "qna.db",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: time.Hour,
+		},
+		Purge: PurgeConfig{
+			Retention: 720 * time.Hour,
+			Interval:  time.Hour,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: 24 * time.Hour,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   RateLimitStoreMemory,
+			Routes: map[string]RouteLimit{
+				"POST /questions/":              {Requests: 10, Period: time.Minute, Burst: 5},
+				"POST /questions/{id}/answers/": {Requests: 20, Period: time.Minute, Burst: 10},
+				"POST /questions/{id}/vote":     {Requests: 60, Period: time.Minute, Burst: 20},
+				"POST /answers/{id}/vote":       {Requests: 60, Period: time.Minute, Burst: 20},
+			},
+		},
+		Tracing: TracingConfig{
+			Exporter: TracingNone,
+			File:     "traces.jsonl",
+		},
+		Log: LogConfig{
+			Level:  slog.LevelInfo,
+			Format: LogFormatText,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the YAML file named by the CONFIG_FILE environment variable
+// if it is set, and the environment variables, in that order. It fails if the file contains unknown settings
+// or if any value is malformed or invalid.
+func Load() (*Config, error) {
+	return load((*Config).Validate)
+}
+
+// LoadForMigrations builds the configuration like Load but validates only the settings the migrate command uses,
+// see ValidateForMigrations, so that secrets of the server like the signing key need not be given to it.
+func LoadForMigrations() (*Config, error) {
+	return load((*Config).ValidateForMigrations)
+}
+
+func load(validate func(*Config) error) (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if cfg.Storage == StoragePostgres {
+		cfg.Storage = StorageDatabase
+	}
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides the settings with the environment variables which are set.
+func (c *Config) applyEnv() error {
+	return errors.Join(
+		envInt("SERVER_PORT", &c.Server.Port),
+		envDuration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout),
+		envDuration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout),
+		envDuration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout),
+		envDuration("SHUTDOWN_DELAY", &c.Server.ShutdownDelay),
+		envDuration("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout),
+		envDuration("READINESS_TIMEOUT", &c.Server.ReadinessTimeout),
+		envBool("SERVER_VALIDATE_REQUESTS", &c.Server.ValidateRequests),
+
+		envString("STORAGE", &c.Storage),
+
+		envString("DB_DRIVER", &c.Database.Driver),
+		envString("DB_HOST", &c.Database.Host),
+		envInt("DB_PORT", &c.Database.Port),
+		envString("DB_USER", &c.Database.User),
+		envString("DB_PASSWORD", (*string)(&c.Database.Password)),
+		envString("DB_NAME", &c.Database.Name),
+		envString("DB_SSLMODE", &c.Database.SSLMode),
+		envString("SQLITE_PATH", &c.Database.SQLitePath),
+		envInt("DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns),
+		envInt("DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns),
+		envDuration("DB_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime),
+		envBool("MIGRATE_ON_START", &c.Database.MigrateOnStart),
+
+		envList("MEMORY_MODERATORS", &c.Memory.Moderators),
+		envList("MEMORY_ADMINS", &c.Memory.Admins),
+
+		envString("AUTH_SIGNING_KEY", (*string)(&c.Auth.SigningKey)),
+
+		envDuration("PURGE_RETENTION", &c.Purge.Retention),
+		envDuration("PURGE_INTERVAL", &c.Purge.Interval),
+
+		envDuration("IDEMPOTENCY_TTL", &c.Idempotency.TTL),
+
+		envBool("RATE_LIMIT_ENABLED", &c.RateLimit.Enabled),
+		envString("RATE_LIMIT_STORE", &c.RateLimit.Store),
+
+		envString("TRACING_EXPORTER", &c.Tracing.Exporter),
+		envString("TRACING_FILE", &c.Tracing.File),
+
+		envLevel("LOG_LEVEL", &c.Log.Level),
+		envString("LOG_FORMAT", &c.Log.Format),
+	)
+}
+
+// Validate reports all invalid settings at once.
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(setting, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("invalid value of %s: %s", setting, fmt.Sprintf(format, args...)))
+	}
+	positive := func(setting string, value time.Duration) {
+		if value <= 0 {
+			invalid(setting, "must be positive")
+		}
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		invalid("server.port", "must be between 1 and 65535")
+	}
+	positive("server.read_timeout", c.Server.ReadTimeout)
+	positive("server.write_timeout", c.Server.WriteTimeout)
+	positive("server.idle_timeout", c.Server.IdleTimeout)
+	positive("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	positive("server.readiness_timeout", c.Server.ReadinessTimeout)
+	if c.Server.ShutdownDelay < 0 {
+		invalid("server.shutdown_delay", "must not be negative")
+	}
+
+	errs = append(errs, c.validateStorage())
+
+	// The key has no default on purpose: a well-known fallback would let anyone forge tokens.
+	if len(c.Auth.SigningKey) < minSigningKeyLength {
+		invalid("auth.signing_key", "must be at least %d bytes long", minSigningKeyLength)
+	}
+
+	positive("purge.retention", c.Purge.Retention)
+	positive("purge.interval", c.Purge.Interval)
+	positive("idempotency.ttl", c.Idempotency.TTL)
+	errs = append(errs, c.RateLimit.validate(c.Storage))
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingOTLP, TracingStdout:
+	case TracingFile:
+		if c.Tracing.File == "" {
+			invalid("tracing.file", "must be set when tracing.exporter is %s", TracingFile)
+		}
+	default:
+		invalid("tracing.exporter", "must be %s, %s, %s or %s", TracingNone, TracingOTLP, TracingStdout, TracingFile)
+	}
+
+	errs = append(errs, c.Log.validate())
+
+	return errors.Join(errs...)
+}
+
+// ValidateForMigrations reports all invalid settings among the storage, the database and the log ones.
+func (c *Config) ValidateForMigrations() error {
+	return errors.Join(c.validateStorage(), c.Log.validate())
+}
+
+func (c *Config) validateStorage() error {
+	switch c.Storage {
+	case StorageMemory:
+		return c.Memory.validate()
+	case StorageDatabase:
+		var err error
+		if len(c.Memory.Moderators) > 0 || len(c.Memory.Admins) > 0 {
+			err = fmt.Errorf("invalid value of memory: must be empty unless storage is %s, roles are kept in the users table", StorageMemory)
+		}
+		return errors.Join(err, c.Database.validate())
+	default:
+		return fmt.Errorf("invalid value of storage: must be %s or %s", StorageDatabase, StorageMemory)
+	}
+}
+
+func (c MemoryConfig) validate() error {
+	var errs []error
+	for _, id := range c.Moderators {
+		if slices.Contains(c.Admins, id) {
+			errs = append(errs, fmt.Errorf("invalid value of memory.admins: %q is listed among the moderators too", id))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c LogConfig) validate() error {
+	if c.Format != LogFormatText && c.Format != LogFormatJSON {
+		return fmt.Errorf("invalid value of log.format: must be %s or %s", LogFormatText, LogFormatJSON)
+	}
+	return nil
+}
+
+func (c DatabaseConfig) validate() error {
+	var errs []error
+	invalid := func(setting, message string) {
+		errs = append(errs, fmt.Errorf("invalid value of database.%s: %s", setting, message))
+	}
+
+	switch c.Driver {
+	case DriverPostgres:
+		if c.Host == "" {
+			invalid("host", "must be set")
+		}
+		if c.Port < 1 || c.Port > 65535 {
+			invalid("port", "must be between 1 and 65535")
+		}
+		if c.User == "" {
+			invalid("user", "must be set")
+		}
+		if c.Password == "" {
+			invalid("password", "must be set")
+		}
+		if c.Name == "" {
+			invalid("name", "must be set")
+		}
+	case DriverSQLite:
+		if c.SQLitePath == "" {
+			invalid("sqlite_path", "must be set")
+		}
+	default:
+		invalid("driver", fmt.Sprintf("must be %s or %s", DriverPostgres, DriverSQLite))
+	}
+
+	if c.MaxOpenConns < 1 {
+		invalid("max_open_conns", "must be positive")
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConns > c.MaxOpenConns {
+		invalid("max_idle_conns", "must be between 0 and max_open_conns")
+	}
+	if c.ConnMaxLifetime < 0 {
+		invalid("conn_max_lifetime", "must not be negative")
+	}
+	return errors.Join(errs...)
+}
+
+func (c RateLimitConfig) validate(storage string) error {
+	var errs []error
+	invalid := func(setting, message string) {
+		errs = append(errs, fmt.Errorf("invalid value of rate_limit.%s: %s", setting, message))
+	}
+
+	switch c.Store {
+	case RateLimitStoreMemory:
+	case RateLimitStoreDatabase:
+		if storage != StorageDatabase {
+			invalid("store", fmt.Sprintf("must be %s unless storage is %s", RateLimitStoreMemory, StorageDatabase))
+		}
+	default:
+		invalid("store", fmt.Sprintf("must be %s or %s", RateLimitStoreMemory, RateLimitStoreDatabase))
+	}
+
+	for _, pattern := range slices.Sorted(maps.Keys(c.Routes)) {
+		method, path, _ := strings.Cut(pattern, " ")
+		if method == "" || !strings.HasPrefix(path, "/") {
+			invalid("routes", fmt.Sprintf("%q is not a route pattern like \"POST /questions/\"", pattern))
+			continue
+		}
+
+		limit := c.Routes[pattern]
+		if limit.Requests < 1 || limit.Period <= 0 || limit.Burst < 1 {
+			invalid("routes", fmt.Sprintf("limit of %q must have positive requests, period and burst", pattern))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DSN builds the connection string for Driver.
+func (c DatabaseConfig) DSN() string {
+	if c.Driver == DriverSQLite {
+		return SQLiteDSN(c.SQLitePath)
+	}
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		quoteDSN(c.Host), c.Port, quoteDSN(c.User), quoteDSN(string(c.Password)), quoteDSN(c.Name), quoteDSN(c.SSLMode),
+	)
+}
+
+// quoteDSN quotes a value of a PostgreSQL keyword/value connection string, so it may contain spaces and quotes.
+func quoteDSN(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// SQLiteDSN builds the connection string of an SQLite database file at path.
+// Foreign keys are enabled to make cascades work, and transactions take the write lock
+// when they begin since SQLite ignores SELECT ... FOR UPDATE.
+func SQLiteDSN(path string) string {
+	return "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+}
+
+// LogValue describes the configuration in logs with the secrets redacted and the durations formatted like "5s".
+func (c *Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Group("server",
+			"port", c.Server.Port,
+			"read_timeout", c.Server.ReadTimeout.String(),
+			"write_timeout", c.Server.WriteTimeout.String(),
+			"idle_timeout", c.Server.IdleTimeout.String(),
+			"shutdown_delay", c.Server.ShutdownDelay.String(),
+			"shutdown_timeout", c.Server.ShutdownTimeout.String(),
+			"readiness_timeout", c.Server.ReadinessTimeout.String(),
+			"validate_requests", c.Server.ValidateRequests,
+		),
+		slog.String("storage", c.Storage),
+		slog.Group("database",
+			"driver", c.Database.Driver,
+			"host", c.Database.Host,
+			"port", c.Database.Port,
+			"user", c.Database.User,
+			"password", c.Database.Password,
+			"name", c.Database.Name,
+			"sslmode", c.Database.SSLMode,
+			"sqlite_path", c.Database.SQLitePath,
+			"max_open_conns", c.Database.MaxOpenConns,
+			"max_idle_conns", c.Database.MaxIdleConns,
+			"conn_max_lifetime", c.Database.ConnMaxLifetime.String(),
+			"migrate_on_start", c.Database.MigrateOnStart,
+		),
+		slog.Group("memory", "moderators", c.Memory.Moderators, "admins", c.Memory.Admins),
+		slog.Group("auth", "signing_key", c.Auth.SigningKey),
+		slog.Group("purge", "retention", c.Purge.Retention.String(), "interval", c.Purge.Interval.String()),
+		slog.Group("idempotency", "ttl", c.Idempotency.TTL.String()),
+		slog.Group("rate_limit", "enabled", c.RateLimit.Enabled, "store", c.RateLimit.Store, "routes", c.RateLimit.routesValue()),
+		slog.Group("tracing", "exporter", c.Tracing.Exporter, "file", c.Tracing.File),
+		slog.Group("log", "level", c.Log.Level, "format", c.Log.Format),
 	)
+}
+
+// routesValue describes each route limit like "10/1m0s, burst 5".
+func (c RateLimitConfig) routesValue() slog.Value {
+	var attrs []slog.Attr
+	for _, pattern := range slices.Sorted(maps.Keys(c.Routes)) {
+		limit := c.Routes[pattern]
+		attrs = append(attrs, slog.String(pattern, fmt.Sprintf("%d/%s, burst %d", limit.Requests, limit.Period, limit.Burst)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func envString(key string, target *string) error {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*target = value
+	}
+	return nil
+}
 
+// envList splits a comma-separated value, ignoring the whitespace around the items and the empty ones.
+func envList(key string, target *[]string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*target = items
 	return nil
 }
 
-func getEnv(key, defaultValue string) string {
+func envInt(key string, target *int) error {
+	return envParse(key, target, strconv.Atoi)
+}
+
+func envBool(key string, target *bool) error {
+	return envParse(key, target, strconv.ParseBool)
+}
+
+func envDuration(key string, target *time.Duration) error {
+	return envParse(key, target, time.ParseDuration)
+}
+
+func envLevel(key string, target *slog.Level) error {
+	return envParse(key, target, func(value string) (slog.Level, error) {
+		var level slog.Level
+		err := level.UnmarshalText([]byte(value))
+		return level, err
+	})
+}
+
+func envParse[T any](key string, target *T, parse func(string) (T, error)) error {
 	value := os.Getenv(key)
 	if value == "" {
-		slog.Warn(fmt.Sprintf("environment variable %s is missing, setting default value: %s", key, defaultValue))
-		return defaultValue
+		return nil
 	}
-	return value
+	parsed, err := parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %w", key, err)
+	}
+	*target = parsed
+	return nil
 }
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningKey = "test-signing-key-which-is-32-bytes-long"
+
+// setEnv clears the variables read by Load and sets the given ones for the duration of the test.
+func setEnv(t *testing.T, env map[string]string) {
+	for _, key := range []string{
+		"CONFIG_FILE", "SERVER_PORT", "SERVER_READ_TIMEOUT", "SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT",
+		"SHUTDOWN_DELAY", "SHUTDOWN_TIMEOUT", "READINESS_TIMEOUT", "SERVER_VALIDATE_REQUESTS", "STORAGE", "DB_DRIVER", "DB_HOST", "DB_PORT",
+		"DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE", "SQLITE_PATH", "DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS",
+		"DB_CONN_MAX_LIFETIME", "MIGRATE_ON_START", "MEMORY_MODERATORS", "MEMORY_ADMINS", "AUTH_SIGNING_KEY", "PURGE_RETENTION", "PURGE_INTERVAL",
+		"IDEMPOTENCY_TTL", "RATE_LIMIT_ENABLED", "RATE_LIMIT_STORE", "TRACING_EXPORTER", "TRACING_FILE", "LOG_LEVEL", "LOG_FORMAT",
+	} {
+		t.Setenv(key, env[key])
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setEnv(t, map[string]string{"DB_PASSWORD": "secret", "AUTH_SIGNING_KEY": testSigningKey})
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	expected := Default()
+	expected.Database.Password = "secret"
+	expected.Auth.SigningKey = testSigningKey
+	assert.Equal(t, expected, cfg)
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  port: 9090
+  read_timeout: 3s
+database:
+  driver: sqlite
+  sqlite_path: file.db
+  max_open_conns: 4
+  max_idle_conns: 2
+auth:
+  signing_key: `+testSigningKey+`
+log:
+  level: debug
+  format: json
+`)
+	setEnv(t, map[string]string{"CONFIG_FILE": path, "SERVER_PORT": "9191", "LOG_LEVEL": "warn"})
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, 9191, cfg.Server.Port)
+	assert.Equal(t, 3*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, 16*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, DriverSQLite, cfg.Database.Driver)
+	assert.Equal(t, SQLiteDSN("file.db"), cfg.Database.DSN())
+	assert.Equal(t, 4, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 2, cfg.Database.MaxIdleConns)
+	assert.Equal(t, slog.LevelWarn, cfg.Log.Level)
+	assert.Equal(t, LogFormatJSON, cfg.Log.Format)
+}
+
+func TestLoad_UnknownFileSetting(t *testing.T) {
+	path := writeConfigFile(t, "server:\n  prot: 9090\n")
+	setEnv(t, map[string]string{"CONFIG_FILE": path, "DB_PASSWORD": "secret", "AUTH_SIGNING_KEY": testSigningKey})
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "prot")
+}
+
+func TestLoad_LegacyPostgresStorage(t *testing.T) {
+	setEnv(t, map[string]string{"STORAGE": "postgres", "DB_PASSWORD": "secret", "AUTH_SIGNING_KEY": testSigningKey})
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, StorageDatabase, cfg.Storage)
+	assert.Equal(t, DriverPostgres, cfg.Database.Driver)
+}
+
+func TestLoadForMigrations(t *testing.T) {
+	setEnv(t, map[string]string{"DB_PASSWORD": "secret", "PURGE_INTERVAL": "0s"})
+
+	cfg, err := LoadForMigrations()
+	require.NoError(t, err, "settings of the server are not required for migrations")
+	assert.Equal(t, "secret", string(cfg.Database.Password))
+
+	setEnv(t, map[string]string{"AUTH_SIGNING_KEY": testSigningKey})
+	_, err = LoadForMigrations()
+	assert.ErrorContains(t, err, "invalid value of database.password:")
+}
+
+func TestLoad_MalformedEnv(t *testing.T) {
+	cases := map[string]string{
+		"SERVER_PORT":        "http",
+		"SHUTDOWN_TIMEOUT":   "soon",
+		"MIGRATE_ON_START":   "maybe",
+		"RATE_LIMIT_ENABLED": "sometimes",
+		"LOG_LEVEL":          "verbose",
+	}
+
+	for key, value := range cases {
+		t.Run(key, func(t *testing.T) {
+			setEnv(t, map[string]string{key: value, "DB_PASSWORD": "secret", "AUTH_SIGNING_KEY": testSigningKey})
+
+			_, err := Load()
+			assert.ErrorContains(t, err, "invalid value of "+key)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		modify  func(cfg *Config)
+		setting string
+	}{
+		"port out of range":     {func(cfg *Config) { cfg.Server.Port = 70000 }, "server.port"},
+		"zero timeout":          {func(cfg *Config) { cfg.Server.WriteTimeout = 0 }, "server.write_timeout"},
+		"unknown storage":       {func(cfg *Config) { cfg.Storage = "disk" }, "storage"},
+		"unknown driver":        {func(cfg *Config) { cfg.Database.Driver = "mysql" }, "database.driver"},
+		"missing password":      {func(cfg *Config) { cfg.Database.Password = "" }, "database.password"},
+		"no connections":        {func(cfg *Config) { cfg.Database.MaxOpenConns = 0 }, "database.max_open_conns"},
+		"too many idle":         {func(cfg *Config) { cfg.Database.MaxIdleConns = 100 }, "database.max_idle_conns"},
+		"short signing key":     {func(cfg *Config) { cfg.Auth.SigningKey = "short" }, "auth.signing_key"},
+		"unknown exporter":      {func(cfg *Config) { cfg.Tracing.Exporter = "jaeger" }, "tracing.exporter"},
+		"unknown log format":    {func(cfg *Config) { cfg.Log.Format = "xml" }, "log.format"},
+		"negative purge period": {func(cfg *Config) { cfg.Purge.Interval = -time.Hour }, "purge.interval"},
+		"unknown limit store":   {func(cfg *Config) { cfg.RateLimit.Store = "redis" }, "rate_limit.store"},
+		"route without method":  {func(cfg *Config) { cfg.RateLimit.Routes["/questions/"] = RouteLimit{1, time.Minute, 1} }, "rate_limit.routes"},
+		"zero burst":            {func(cfg *Config) { cfg.RateLimit.Routes["GET /tags"] = RouteLimit{1, time.Minute, 0} }, "rate_limit.routes"},
+		"roles in database":     {func(cfg *Config) { cfg.Memory.Moderators = []string{"moderator"} }, "memory"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Database.Password = "secret"
+			cfg.Auth.SigningKey = testSigningKey
+			require.NoError(t, cfg.Validate())
+
+			c.modify(cfg)
+			assert.ErrorContains(t, cfg.Validate(), "invalid value of "+c.setting+":")
+		})
+	}
+}
+
+func TestValidate_MemoryStorageIgnoresDatabase(t *testing.T) {
+	cfg := Default()
+	cfg.Storage = StorageMemory
+	cfg.Auth.SigningKey = testSigningKey
+
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_DatabaseRateLimitStoreNeedsDatabase(t *testing.T) {
+	cfg := Default()
+	cfg.Storage = StorageMemory
+	cfg.Auth.SigningKey = testSigningKey
+	cfg.RateLimit.Store = RateLimitStoreDatabase
+
+	assert.ErrorContains(t, cfg.Validate(), "invalid value of rate_limit.store:")
+}
+
+func TestLoad_MemoryRoles(t *testing.T) {
+	path := writeConfigFile(t, `
+storage: memory
+memory:
+  moderators: [moderator]
+  admins: [admin]
+`)
+	setEnv(t, map[string]string{"CONFIG_FILE": path, "AUTH_SIGNING_KEY": testSigningKey, "MEMORY_MODERATORS": " first, second,,"})
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first", "second"}, cfg.Memory.Moderators)
+	assert.Equal(t, []string{"admin"}, cfg.Memory.Admins)
+
+	cfg.Memory.Admins = []string{"second"}
+	assert.ErrorContains(t, cfg.Validate(), "invalid value of memory.admins:")
+}
+
+func TestLoad_RateLimitRoutes(t *testing.T) {
+	path := writeConfigFile(t, `
+rate_limit:
+  store: database
+  routes:
+    GET /tags:
+      requests: 5
+      period: 1s
+      burst: 10
+`)
+	setEnv(t, map[string]string{"CONFIG_FILE": path, "DB_PASSWORD": "secret", "AUTH_SIGNING_KEY": testSigningKey, "RATE_LIMIT_ENABLED": "false"})
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.False(t, cfg.RateLimit.Enabled)
+	assert.Equal(t, RateLimitStoreDatabase, cfg.RateLimit.Store)
+	assert.Equal(t, RouteLimit{Requests: 5, Period: time.Second, Burst: 10}, cfg.RateLimit.Routes["GET /tags"])
+	assert.Contains(t, cfg.RateLimit.Routes, "POST /questions/", "the default routes are kept")
+}
+
+func TestDSN_QuotesValues(t *testing.T) {
+	cfg := Default().Database
+	cfg.Password = `it's a secret`
+
+	assert.Equal(t, `host='db' port=5432 user='postgres' password='it\'s a secret' dbname='qna_db' sslmode='disable'`, cfg.DSN())
+}
+
+func TestLogValue_RedactsSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "db-password"
+	cfg.Auth.SigningKey = testSigningKey
+
+	for name, handler := range map[string]func(*bytes.Buffer) slog.Handler{
+		"text": func(buf *bytes.Buffer) slog.Handler { return slog.NewTextHandler(buf, nil) },
+		"json": func(buf *bytes.Buffer) slog.Handler { return slog.NewJSONHandler(buf, nil) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(handler(&buf)).Info("configuration loaded", "config", cfg)
+
+			assert.NotContains(t, buf.String(), "db-password")
+			assert.NotContains(t, buf.String(), testSigningKey)
+			assert.Contains(t, buf.String(), redacted)
+			assert.Contains(t, buf.String(), "max_open_conns")
+		})
+	}
+}
@@ -12,27 +12,28 @@ func createAnswer(svc service.AnswerService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		questionID, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
 		if err != nil {
-			http.Error(w, "invalid question id", http.StatusBadRequest)
+			writeProblem(w, r, errInvalidQuestionID)
 			return
 		}
 
 		rbody := struct {
-			UserID string `json:"user_id"`
 			Text string `json:"text"`
 		}{}
 
 		if err := json.NewDecoder(r.Body).Decode(&rbody); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeProblem(w, r, malformedBody(err))
 			return
 		}
 
-		answer, err := svc.Create(r.Context(), uint(questionID), rbody.UserID, rbody.Text)
+		answer, err := svc.Create(r.Context(), uint(questionID), rbody.Text)
 		if err != nil {
-			http.Error(w, err.Error(), errorStatusCode(err))
+			writeProblem(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", entityTag(answer.Version))
+		w.Header().Set("Location", "/answers/"+strconv.FormatUint(uint64(answer.ID), 10))
 		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(answer)
 	}
@@ -42,13 +43,20 @@ func getAnswer(svc service.AnswerService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
 		if err != nil {
-			http.Error(w, "invalid answer id", http.StatusBadRequest)
+			writeProblem(w, r, errInvalidAnswerID)
 			return
 		}
 
 		answer, err := svc.GetByID(r.Context(), uint(id))
 		if err != nil {
-			http.Error(w, err.Error(), errorStatusCode(err))
+			writeProblem(w, r, err)
+			return
+		}
+
+		etag := entityTag(answer.Version)
+		w.Header().Set("ETag", etag)
+		if notModified(r, etag) {
+			w.WriteHeader(http.StatusNotModified)
 			return
 		}
 
@@ -61,15 +69,160 @@ func deleteAnswer(svc service.AnswerService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
 		if err != nil {
-			http.Error(w, "invalid answer id", http.StatusBadRequest)
+			writeProblem(w, r, errInvalidAnswerID)
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
 			return
 		}
 
-		if err := svc.Delete(r.Context(), uint(id)); err != nil {
-			http.Error(w, err.Error(), errorStatusCode(err))
+		if err := svc.Delete(r.Context(), uint(id), version); err != nil {
+			writeProblem(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+func restoreAnswer(svc service.AnswerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidAnswerID)
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		answer, err := svc.Restore(r.Context(), uint(id), version)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", entityTag(answer.Version))
+		json.NewEncoder(w).Encode(answer)
+	}
+}
+
+func voteAnswer(svc service.AnswerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidAnswerID)
+			return
+		}
+
+		rbody := struct {
+			Value int `json:"value"`
+		}{}
+
+		if err := json.NewDecoder(r.Body).Decode(&rbody); err != nil {
+			writeProblem(w, r, malformedBody(err))
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		summary, err := svc.Vote(r.Context(), uint(id), rbody.Value, version)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summary)
+	}
+}
+
+func unvoteAnswer(svc service.AnswerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidAnswerID)
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		summary, err := svc.Unvote(r.Context(), uint(id), version)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summary)
+	}
+}
+
+func updateAnswer(svc service.AnswerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidAnswerID)
+			return
+		}
+
+		rbody := struct {
+			Text string `json:"text"`
+		}{}
+
+		if err := json.NewDecoder(r.Body).Decode(&rbody); err != nil {
+			writeProblem(w, r, malformedBody(err))
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		answer, err := svc.Update(r.Context(), uint(id), rbody.Text, version)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", entityTag(answer.Version))
+		json.NewEncoder(w).Encode(answer)
+	}
+}
+
+func getAnswerRevisions(svc service.AnswerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidAnswerID)
+			return
+		}
+
+		revisions, err := svc.GetRevisions(r.Context(), uint(id))
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(revisions)
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/mocks"
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testSigningKey = []byte("test-signing-key-which-is-32-bytes-long")
+
+func TestAuthMiddleware_ValidToken(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := AuthMiddleware(auth.NewVerifier(testSigningKey))(NewRouter(questionService, nil, nil))
+
+	mockQuestionRepo.On("Create", mock.Anything, mock.MatchedBy(func(q *model.Question) bool {
+		return q.AuthorID == testUserID
+	})).Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}, nil)
+
+	token, err := auth.NewToken(testSigningKey, testUserID, time.Minute)
+	require.NoError(t, err)
+
+	body, _ := json.Marshal(map[string]string{"text": "Test question"})
+	req := httptest.NewRequest("POST", "/questions/", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_InvalidToken(t *testing.T) {
+	expired, err := auth.NewToken(testSigningKey, testUserID, -time.Minute)
+	require.NoError(t, err)
+	foreign, err := auth.NewToken([]byte("another-signing-key-which-is-32-bytes"), testUserID, time.Minute)
+	require.NoError(t, err)
+
+	cases := map[string]string{
+		"expired":        "Bearer " + expired,
+		"foreign key":    "Bearer " + foreign,
+		"malformed":      "Bearer not.a.token",
+		"unknown scheme": "Basic dXNlcjpwYXNz",
+	}
+
+	for name, header := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockQuestionRepo := new(mocks.MockQuestionRepository)
+			mockUserRepo := new(mocks.MockUserRepository)
+			questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+			handler := AuthMiddleware(auth.NewVerifier(testSigningKey))(NewRouter(questionService, nil, nil))
+
+			req := httptest.NewRequest("GET", "/questions/1", nil)
+			req.Header.Set("Authorization", header)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+			assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+			mockQuestionRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthMiddleware_Anonymous(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := AuthMiddleware(auth.NewVerifier(testSigningKey))(NewRouter(questionService, nil, nil))
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).Return(&model.Question{ID: 1, Text: "Test question"}, nil)
+
+	req := httptest.NewRequest("GET", "/questions/1", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	body, _ := json.Marshal(map[string]string{"text": "Test question"})
+	req = httptest.NewRequest("POST", "/questions/", bytes.NewReader(body))
+	rr = httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrUnauthenticated.Error())
+
+	mockQuestionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ppb03/qna-api/internal/service"
+)
+
+var errMultipleEntityTags = errors.New("If-Match must hold a single entity tag or *")
+
+// entityTag formats the version of a question or an answer as a strong entity tag.
+func entityTag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// entityTags splits the comma-separated lists of the given header into entity tags.
+func entityTags(r *http.Request, header string) []string {
+	var tags []string
+	for _, value := range r.Header.Values(header) {
+		for tag := range strings.SplitSeq(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// notModified reports whether the If-None-Match header of a GET request matches etag.
+// As required by RFC 9110, the comparison is weak, so W/"1" matches "1".
+func notModified(r *http.Request, etag string) bool {
+	for _, tag := range entityTags(r, "If-None-Match") {
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion returns the version required by the If-Match header. Zero means any version,
+// either because the header is missing or because it is "*" which is satisfied by any existing resource.
+// A weak or foreign entity tag never matches under the strong comparison required for If-Match.
+func ifMatchVersion(r *http.Request) (int, error) {
+	tags := entityTags(r, "If-Match")
+	switch {
+	case len(tags) == 0 || len(tags) == 1 && tags[0] == "*":
+		return 0, nil
+	case len(tags) > 1:
+		return 0, errMultipleEntityTags
+	}
+
+	version, err := strconv.Atoi(strings.Trim(tags[0], `"`))
+	if err != nil || version < 1 || entityTag(version) != tags[0] {
+		return 0, service.ErrPreconditionFailed
+	}
+	return version, nil
+}
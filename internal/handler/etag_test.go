@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type conditionalFixture struct {
+	router    http.Handler
+	questions repository.QuestionRepository
+	answers   repository.AnswerRepository
+}
+
+func newConditionalFixture(t *testing.T) conditionalFixture {
+	router, backend := newMemoryRouter(repository.NewMemoryUserRepository())
+
+	ctx := context.Background()
+	_, err := backend.questions.Create(ctx, &model.Question{AuthorID: testUserID, Text: "Test question"})
+	require.NoError(t, err)
+	_, err = backend.answers.Create(ctx, &model.Answer{QuestionID: 1, UserID: testUserID, Text: "Test answer"})
+	require.NoError(t, err)
+
+	return conditionalFixture{router: router, questions: backend.questions, answers: backend.answers}
+}
+
+func (f conditionalFixture) do(method, path, body string, header http.Header) *httptest.ResponseRecorder {
+	req := withUser(httptest.NewRequest(method, path, strings.NewReader(body)), testUserID)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rr := httptest.NewRecorder()
+	f.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGetQuestion_ETag(t *testing.T) {
+	f := newConditionalFixture(t)
+
+	rr := f.do("GET", "/questions/1", "", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	assert.Equal(t, `"2"`, etag, "the answer changed the question")
+
+	for _, header := range []string{etag, "W/" + etag, `"1", ` + etag, "*"} {
+		rr = f.do("GET", "/questions/1", "", http.Header{"If-None-Match": {header}})
+		assert.Equal(t, http.StatusNotModified, rr.Code, header)
+		assert.Equal(t, etag, rr.Header().Get("ETag"))
+		assert.Empty(t, rr.Body.String())
+	}
+
+	_, err := f.answers.Vote(context.Background(), 1, testOtherUserID, 1, 0)
+	require.NoError(t, err)
+
+	rr = f.do("GET", "/questions/1", "", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusOK, rr.Code, "voting for an answer changes the question")
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+}
+
+func TestGetAnswer_ETag(t *testing.T) {
+	f := newConditionalFixture(t)
+
+	rr := f.do("GET", "/answers/1", "", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+
+	rr = f.do("GET", "/answers/1", "", http.Header{"If-None-Match": {`"1"`}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	rr = f.do("GET", "/answers/1", "", http.Header{"If-None-Match": {`"2"`}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUpdateQuestion_IfMatch(t *testing.T) {
+	f := newConditionalFixture(t)
+
+	rr := f.do("PATCH", "/questions/1", `{"text": "Edited question"}`, http.Header{"If-Match": {`"2"`}})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+
+	rr = f.do("PATCH", "/questions/1", `{"text": "Lost update"}`, http.Header{"If-Match": {`"2"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	assert.Equal(t, "urn:qna-api:problem:precondition-failed", problemTypeOf(t, rr))
+
+	question, err := f.questions.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Edited question", question.Text)
+}
+
+func TestUpdateAnswer_IfMatch(t *testing.T) {
+	f := newConditionalFixture(t)
+
+	rr := f.do("PATCH", "/answers/1", `{"text": "Lost update"}`, http.Header{"If-Match": {`"2"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+	rr = f.do("PATCH", "/answers/1", `{"text": "Edited answer"}`, http.Header{"If-Match": {`"1"`}})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+}
+
+func TestAcceptAnswer_IfMatch(t *testing.T) {
+	f := newConditionalFixture(t)
+
+	rr := f.do("POST", "/questions/1/accept/1", "", http.Header{"If-Match": {`"1"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+	rr = f.do("POST", "/questions/1/accept/1", "", http.Header{"If-Match": {`"2"`}})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+}
+
+func TestDelete_IfMatch(t *testing.T) {
+	f := newConditionalFixture(t)
+
+	rr := f.do("DELETE", "/answers/1", "", http.Header{"If-Match": {`"7"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	rr = f.do("DELETE", "/answers/1", "", http.Header{"If-Match": {`"1"`}})
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = f.do("DELETE", "/questions/1", "", http.Header{"If-Match": {`"2"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code, "deleting the answer changed the question")
+	rr = f.do("DELETE", "/questions/1", "", http.Header{"If-Match": {`"3"`}})
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = f.do("DELETE", "/questions/1", "", http.Header{"If-Match": {`"3"`}})
+	assert.Equal(t, http.StatusNotFound, rr.Code, "a missing question is reported as such")
+}
+
+func TestVote_IfMatch(t *testing.T) {
+	f := newConditionalFixture(t)
+
+	rr := f.do("POST", "/questions/1/vote", `{"value": 1}`, http.Header{"If-Match": {`"1"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	rr = f.do("POST", "/questions/1/vote", `{"value": 1}`, http.Header{"If-Match": {`"2"`}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = f.do("DELETE", "/questions/1/vote", "", http.Header{"If-Match": {`"2"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code, "the vote changed the question")
+	rr = f.do("DELETE", "/questions/1/vote", "", http.Header{"If-Match": {`"3"`}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = f.do("POST", "/answers/1/vote", `{"value": -1}`, http.Header{"If-Match": {`"2"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	rr = f.do("POST", "/answers/1/vote", `{"value": -1}`, http.Header{"If-Match": {`"1"`}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = f.do("DELETE", "/answers/1/vote", "", http.Header{"If-Match": {`"1"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	rr = f.do("DELETE", "/answers/1/vote", "", http.Header{"If-Match": {`"2"`}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	answer, err := f.answers.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Zero(t, answer.Score)
+}
+
+func TestIfMatch_Malformed(t *testing.T) {
+	cases := map[string]struct {
+		header  string
+		status  int
+		problem string
+	}{
+		"weak":          {`W/"2"`, http.StatusPreconditionFailed, "precondition-failed"},
+		"unquoted":      {"2", http.StatusPreconditionFailed, "precondition-failed"},
+		"not a version": {`"abc"`, http.StatusPreconditionFailed, "precondition-failed"},
+		"leading zero":  {`"02"`, http.StatusPreconditionFailed, "precondition-failed"},
+		"several tags":  {`"1", "2"`, http.StatusBadRequest, "multiple-entity-tags"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := newConditionalFixture(t)
+
+			rr := f.do("PATCH", "/questions/1", `{"text": "Edited question"}`, http.Header{"If-Match": {c.header}})
+
+			assert.Equal(t, c.status, rr.Code)
+			assert.Equal(t, "urn:qna-api:problem:"+c.problem, problemTypeOf(t, rr))
+		})
+	}
+}
+
+func TestIfMatch_AnyVersion(t *testing.T) {
+	f := newConditionalFixture(t)
+
+	rr := f.do("PATCH", "/questions/1", `{"text": "Edited question"}`, http.Header{"If-Match": {"*"}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
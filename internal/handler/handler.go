@@ -2,59 +2,180 @@
 package handler
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/logging"
 	"github.com/ppb03/qna-api/internal/service"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
-// LoggingMiddleware adds logging to each request with measurement of the time spent on servicing it.
+// requestIDHeader carries the ID of a request chosen by the client or a proxy in front of the API.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits the length of request IDs accepted from clients.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware identifies each request with the ID from the X-Request-ID header, or with a new random ID
+// if the header is missing or invalid, and returns the ID in the same response header. The ID and a logger annotated
+// with it, as well as with the trace ID if the request is traced, are put into the request context.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		if span := trace.SpanContextFromContext(r.Context()); span.IsValid() {
+			logger = logger.With("trace_id", span.TraceID().String())
+		}
+
+		ctx := logging.ContextWithRequestID(r.Context(), id)
+		next.ServeHTTP(w, r.WithContext(logging.ContextWithLogger(ctx, logger)))
+	})
+}
+
+// validRequestID reports whether id is short and made of printable ASCII characters, so it is safe to log and echo.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// requestInfo collects details of a request learned by inner middleware for the completion log line.
+type requestInfo struct {
+	userID string
+}
+
+type requestInfoKey struct{}
+
+// LoggingMiddleware logs the start and the completion of each request with the logger from the request context.
+// The completion line carries the status code, the number of bytes written, the authenticated user and the time spent.
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		slog.Info("request started", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+		logger := logging.FromContext(r.Context())
+		logger.Info("request started", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
 
-		next.ServeHTTP(w, r)
+		info := &requestInfo{}
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), requestInfoKey{}, info)))
 
-		duration := time.Since(start)
-		slog.Info("request completed", "method", r.Method, "path", r.URL.Path, "duration", duration)
+		logger.Info("request completed", "method", r.Method, "path", r.URL.Path, "status", recorder.status,
+			"bytes", recorder.bytes, "user_id", info.userID, "duration", time.Since(start))
 	})
 }
 
+// statusRecorder remembers the status code and counts the bytes written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if !sr.wroteHeader {
+		sr.status = status
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	sr.wroteHeader = true
+	n, err := sr.ResponseWriter.Write(b)
+	sr.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// AuthMiddleware authenticates requests carrying a bearer token and puts the token subject into the request context.
+// Requests without the Authorization header pass through anonymously, requests with an invalid token are rejected.
+func AuthMiddleware(verifier *auth.Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, _ := strings.Cut(header, " ")
+			if !strings.EqualFold(scheme, "Bearer") {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeProblem(w, r, auth.ErrInvalidToken)
+				return
+			}
+
+			subject, err := verifier.Verify(strings.TrimSpace(token))
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				writeProblem(w, r, auth.ErrInvalidToken)
+				return
+			}
+
+			if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+				info.userID = subject
+			}
+			ctx := auth.ContextWithSubject(r.Context(), subject)
+			ctx = logging.ContextWithLogger(ctx, logging.FromContext(ctx).With("user_id", subject))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // NewRouter creates a new HTTP serve mux with registered handlers.
-func NewRouter(questionService service.QuestionService, answerService service.AnswerService) *http.ServeMux {
+func NewRouter(questionService service.QuestionService, answerService service.AnswerService, searchService service.SearchService) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("POST /questions/", createQuestion(questionService))
 	mux.HandleFunc("DELETE /questions/{id}", deleteQuestion(questionService))
+	mux.HandleFunc("POST /questions/{id}/restore", restoreQuestion(questionService))
 	mux.HandleFunc("GET /questions/{id}", getQuestionByID(questionService))
+	mux.HandleFunc("PATCH /questions/{id}", updateQuestion(questionService))
+	mux.HandleFunc("GET /questions/{id}/revisions", getQuestionRevisions(questionService))
+	mux.HandleFunc("POST /questions/{id}/vote", voteQuestion(questionService))
+	mux.HandleFunc("DELETE /questions/{id}/vote", unvoteQuestion(questionService))
+	mux.HandleFunc("POST /questions/{id}/accept/{answerID}", acceptAnswer(questionService))
 	mux.HandleFunc("GET /questions/", getAllQuestions(questionService))
+	mux.HandleFunc("GET /tags", getTags(questionService))
 
 	mux.HandleFunc("POST /questions/{id}/answers/", createAnswer(answerService))
 	mux.HandleFunc("DELETE /answers/{id}", deleteAnswer(answerService))
+	mux.HandleFunc("POST /answers/{id}/restore", restoreAnswer(answerService))
 	mux.HandleFunc("GET /answers/{id}", getAnswer(answerService))
+	mux.HandleFunc("PATCH /answers/{id}", updateAnswer(answerService))
+	mux.HandleFunc("GET /answers/{id}/revisions", getAnswerRevisions(answerService))
+	mux.HandleFunc("POST /answers/{id}/vote", voteAnswer(answerService))
+	mux.HandleFunc("DELETE /answers/{id}/vote", unvoteAnswer(answerService))
 
-	return mux
-}
-
-// ! Костыль.
-func errorStatusCode(err error) int {
-	serviceErrMapping := map[error]int{
-		service.ErrEmptyText:     http.StatusBadRequest,
-		service.ErrEmptyUserID:   http.StatusBadRequest,
-		service.ErrInvalidUserID: http.StatusBadRequest,
-
-		service.ErrQuestionNotExists: http.StatusNotFound,
-		service.ErrAnswerNotExists:   http.StatusNotFound,
+	mux.HandleFunc("GET /search", search(searchService))
 
-		service.ErrRepositoryFailure: http.StatusInternalServerError,
-	}
-
-	statusCode, ok := serviceErrMapping[err]
-	if !ok {
-		statusCode = http.StatusInternalServerError
-	}
-
-	return statusCode
+	return mux
 }
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ppb03/qna-api/internal/auth"
 	"github.com/ppb03/qna-api/internal/mocks"
 	"github.com/ppb03/qna-api/internal/model"
 	"github.com/ppb03/qna-api/internal/repository"
@@ -16,186 +19,886 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+const (
+	testUserID      = "123e4567-e89b-12d3-a456-426614174000"
+	testOtherUserID = "9b2f3c1e-7d4a-4e8b-a1c2-0f5e6d7c8b9a"
+	testModeratorID = "5f0c6a2b-3e1d-4c7f-9a8b-2d4e6f8a0b1c"
+)
+
+// withUser returns a copy of req authenticated as userID.
+func withUser(req *http.Request, userID string) *http.Request {
+	return req.WithContext(auth.ContextWithSubject(req.Context(), userID))
+}
+
 func TestCreateQuestion_Success(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	handler := NewRouter(questionService, nil)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	expectedQuestion := &model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}
+	
+	mockQuestionRepo.On("Create", mock.Anything, mock.MatchedBy(func(q *model.Question) bool {
+		return q.Text == expectedQuestion.Text && q.AuthorID == testUserID
+	})).Return(expectedQuestion, nil)
+
+	requestBody := map[string]string{"text": "Test question"}
+	body, _ := json.Marshal(requestBody)
+
+	req := withUser(httptest.NewRequest("POST", "/questions/", bytes.NewReader(body)), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var response model.Question
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuestion.ID, response.ID)
+	assert.Equal(t, expectedQuestion.Text, response.Text)
+	assert.Equal(t, testUserID, response.AuthorID)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestCreateQuestion_NormalizesTags(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("Create", mock.Anything, mock.MatchedBy(func(q *model.Question) bool {
+		return assert.ObjectsAreEqual([]model.Tag{{Name: "go"}, {Name: "postgres"}}, q.Tags)
+	})).Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question", Tags: []model.Tag{{Name: "go"}, {Name: "postgres"}}}, nil)
+
+	body, _ := json.Marshal(map[string]any{"text": "Test question", "tags": []string{" Go ", "postgres", "GO"}})
+
+	req := withUser(httptest.NewRequest("POST", "/questions/", bytes.NewReader(body)), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var response struct {
+		Tags []string `json:"tags"`
+	}
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "postgres"}, response.Tags)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestCreateQuestion_ErrInvalidTag(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	for name, tags := range map[string][]string{
+		"blank":    {"go", "  "},
+		"too long": {strings.Repeat("a", service.MaxTagLength+1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]any{"text": "Test question", "tags": tags})
+
+			req := withUser(httptest.NewRequest("POST", "/questions/", bytes.NewReader(body)), testUserID)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, rr.Body.String(), service.ErrInvalidTag.Error())
+		})
+	}
+	mockQuestionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateQuestion_ErrEmptyText(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	requestBody := map[string]string{"text": ""}
+	body, _ := json.Marshal(requestBody)
+
+	req := withUser(httptest.NewRequest("POST", "/questions/", bytes.NewReader(body)), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrEmptyText.Error())
+}
+
+func TestGetQuestion_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	expectedQuestion := &model.Question{
+		ID:      1,
+		Text:    "Test question",
+		Answers: []model.Answer{},
+	}
+	
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(expectedQuestion, nil)
+
+	req := httptest.NewRequest("GET", "/questions/1", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.Question
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuestion.ID, response.ID)
+	assert.Equal(t, expectedQuestion.Text, response.Text)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestGetQuestion_ErrQuestionNotExists(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(999)).
+		Return((*model.Question)(nil), repository.ErrQuestionNotFound)
+
+	req := httptest.NewRequest("GET", "/questions/999", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrQuestionNotExists.Error())
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestGetQuestion_ErrInvalidID(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	req := httptest.NewRequest("GET", "/questions/-123", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid question ID")
+}
+
+func TestGetAllQuestions_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	expectedQuestions := []model.Question{
+		{ID: 1, Text: "Question 1"},
+		{ID: 2, Text: "Question 2"},
+	}
+	mockQuestionRepo.On("GetAll", mock.Anything, repository.QuestionQuery{Limit: service.DefaultPageSize + 1}).
+		Return(expectedQuestions, nil)
+
+	req := httptest.NewRequest("GET", "/questions/", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.QuestionPage
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Questions, 2)
+	assert.Equal(t, expectedQuestions[0].ID, response.Questions[0].ID)
+	assert.Equal(t, expectedQuestions[0].Text, response.Questions[0].Text)
+	assert.Empty(t, response.NextCursor)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestGetAllQuestions_FilterByTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		matchAll bool
+	}{
+		{name: "all by default", query: "/questions/?tag=Go&tag=postgres", matchAll: true},
+		{name: "all", query: "/questions/?tag=Go&tag=postgres&match=all", matchAll: true},
+		{name: "any", query: "/questions/?tag=Go&tag=postgres&match=any", matchAll: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuestionRepo := new(mocks.MockQuestionRepository)
+			mockUserRepo := new(mocks.MockUserRepository)
+			questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+			handler := NewRouter(questionService, nil, nil)
+
+			expectedQuery := repository.QuestionQuery{
+				Limit:        service.DefaultPageSize + 1,
+				Tags:         []string{"go", "postgres"},
+				MatchAllTags: tt.matchAll,
+			}
+			mockQuestionRepo.On("GetAll", mock.Anything, expectedQuery).
+				Return([]model.Question{{ID: 1, Text: "Question 1", Tags: []model.Tag{{Name: "go"}, {Name: "postgres"}}}}, nil)
+
+			req := httptest.NewRequest("GET", tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			mockQuestionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAllQuestions_ErrInvalidTagMatch(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	req := httptest.NewRequest("GET", "/questions/?tag=go&match=some", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrInvalidTagMatch.Error())
+	mockQuestionRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+func TestGetTags_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	expectedTags := []model.TagCount{{Name: "go", Count: 3}, {Name: "postgres", Count: 1}}
+	mockQuestionRepo.On("GetTags", mock.Anything).Return(expectedTags, nil)
+
+	req := httptest.NewRequest("GET", "/tags", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response []model.TagCount
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTags, response)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestGetAllQuestions_Pagination(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC)
+	firstPage := []model.Question{
+		{ID: 1, Text: "Question 1", CreatedAt: createdAt},
+		{ID: 2, Text: "Question 2", CreatedAt: createdAt},
+		{ID: 3, Text: "Question 3", CreatedAt: createdAt},
+	}
+	secondPage := []model.Question{
+		{ID: 3, Text: "Question 3", CreatedAt: createdAt},
+	}
+
+	mockQuestionRepo.On("GetAll", mock.Anything, repository.QuestionQuery{Limit: 3}).
+		Return(firstPage, nil)
+	mockQuestionRepo.On("GetAll", mock.Anything, repository.QuestionQuery{
+		After: &repository.Cursor{CreatedAt: createdAt, ID: 2},
+		Limit: 3,
+	}).Return(secondPage, nil)
+
+	req := httptest.NewRequest("GET", "/questions/?limit=2", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.QuestionPage
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Questions, 2)
+	assert.Equal(t, uint(2), response.Questions[1].ID)
+	assert.NotEmpty(t, response.NextCursor)
+
+	req = httptest.NewRequest("GET", "/questions/?limit=2&cursor="+response.NextCursor, nil)
+	rr = httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	response = model.QuestionPage{}
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Questions, 1)
+	assert.Equal(t, uint(3), response.Questions[0].ID)
+	assert.Empty(t, response.NextCursor)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestGetAllQuestions_ErrInvalidLimit(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	for _, limit := range []string{"abc", "0", "-1", "101"} {
+		req := httptest.NewRequest("GET", "/questions/?limit="+limit, nil)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, "limit=%s", limit)
+		assert.Contains(t, rr.Body.String(), service.ErrInvalidLimit.Error())
+	}
+
+	mockQuestionRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+func TestGetAllQuestions_ErrInvalidCursor(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	req := httptest.NewRequest("GET", "/questions/?cursor=not-a-cursor", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrInvalidCursor.Error())
+
+	mockQuestionRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+func TestDeleteQuestion_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}, nil)
+	mockQuestionRepo.On("Delete", mock.Anything, uint(1), 0).Return(nil)
+
+	req := withUser(httptest.NewRequest("DELETE", "/questions/1", nil), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockQuestionRepo.AssertExpectations(t)
+	mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestDeleteQuestion_Moderator(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}, nil)
+	mockQuestionRepo.On("Delete", mock.Anything, uint(1), 0).Return(nil)
+	mockUserRepo.On("GetByID", mock.Anything, testModeratorID).
+		Return(&model.User{ID: testModeratorID, Role: model.RoleModerator}, nil)
+
+	req := withUser(httptest.NewRequest("DELETE", "/questions/1", nil), testModeratorID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockQuestionRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestDeleteQuestion_ErrForbidden(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, testOtherUserID).
+		Return((*model.User)(nil), repository.ErrUserNotFound)
+
+	req := withUser(httptest.NewRequest("DELETE", "/questions/1", nil), testOtherUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrForbidden.Error())
+
+	mockQuestionRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestDeleteQuestion_ErrUnauthenticated(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	req := httptest.NewRequest("DELETE", "/questions/1", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockQuestionRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteQuestion_ErrQuestionNotExists(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(999)).
+		Return((*model.Question)(nil), repository.ErrQuestionNotFound)
+
+	req := withUser(httptest.NewRequest("DELETE", "/questions/999", nil), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrQuestionNotExists.Error())
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestUpdateQuestion_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	expectedQuestion := &model.Question{ID: 1, AuthorID: testUserID, Text: "Edited question"}
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}, nil)
+	mockQuestionRepo.On("Update", mock.Anything, uint(1), "Edited question", testUserID, 0).
+		Return(expectedQuestion, nil)
+
+	requestBody := map[string]string{"text": "Edited question"}
+	body, _ := json.Marshal(requestBody)
+
+	req := withUser(httptest.NewRequest("PATCH", "/questions/1", bytes.NewReader(body)), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.Question
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuestion.Text, response.Text)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestUpdateQuestion_ErrQuestionNotExists(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(999)).
+		Return((*model.Question)(nil), repository.ErrQuestionNotFound)
+
+	requestBody := map[string]string{"text": "Edited question"}
+	body, _ := json.Marshal(requestBody)
+
+	req := withUser(httptest.NewRequest("PATCH", "/questions/999", bytes.NewReader(body)), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrQuestionNotExists.Error())
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestUpdateQuestion_ErrUnauthenticated(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	requestBody := map[string]string{"text": "Edited question"}
+	body, _ := json.Marshal(requestBody)
+
+	req := httptest.NewRequest("PATCH", "/questions/1", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrUnauthenticated.Error())
+
+	mockQuestionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetQuestionRevisions_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	questionID := uint(1)
+	mockQuestionRepo.On("GetByID", mock.Anything, questionID).
+		Return(&model.Question{ID: questionID, Text: "How do I sort a map in Go?"}, nil)
+	mockQuestionRepo.On("GetRevisions", mock.Anything, questionID).Return([]model.Revision{
+		{ID: 1, QuestionID: &questionID, Text: "How to srot a map", EditorID: testUserID},
+		{ID: 2, QuestionID: &questionID, Text: "How to sort a map", EditorID: testUserID},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/questions/1/revisions", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response []model.Revision
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 2)
+	assert.Equal(t, []model.DiffOp{
+		{Op: model.DiffEqual, Text: "How to"},
+		{Op: model.DiffDelete, Text: "srot"},
+		{Op: model.DiffInsert, Text: "sort"},
+		{Op: model.DiffEqual, Text: "a map"},
+	}, response[0].Diff)
+	assert.Equal(t, []model.DiffOp{
+		{Op: model.DiffEqual, Text: "How"},
+		{Op: model.DiffDelete, Text: "to"},
+		{Op: model.DiffInsert, Text: "do I"},
+		{Op: model.DiffEqual, Text: "sort a map"},
+		{Op: model.DiffInsert, Text: "in Go?"},
+	}, response[1].Diff)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestRestoreQuestion_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	expectedQuestion := &model.Question{
+		ID:      1,
+		Text:    "Test question",
+		Answers: []model.Answer{{ID: 1, QuestionID: 1, Text: "Test answer"}},
+	}
+
+	mockUserRepo.On("GetByID", mock.Anything, testModeratorID).
+		Return(&model.User{ID: testModeratorID, Role: model.RoleModerator}, nil)
+	mockQuestionRepo.On("Restore", mock.Anything, uint(1), 0).Return(nil)
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).Return(expectedQuestion, nil)
+
+	req := withUser(httptest.NewRequest("POST", "/questions/1/restore", nil), testModeratorID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.Question
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedQuestion.ID, response.ID)
+	assert.Len(t, response.Answers, 1)
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestRestoreQuestion_ErrQuestionNotExists(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockUserRepo.On("GetByID", mock.Anything, testModeratorID).
+		Return(&model.User{ID: testModeratorID, Role: model.RoleAdmin}, nil)
+	mockQuestionRepo.On("Restore", mock.Anything, uint(999), 0).Return(repository.ErrQuestionNotFound)
+
+	req := withUser(httptest.NewRequest("POST", "/questions/999/restore", nil), testModeratorID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrQuestionNotExists.Error())
+
+	mockQuestionRepo.AssertExpectations(t)
+}
+
+func TestRestoreQuestion_ErrPreconditionFailed(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockUserRepo.On("GetByID", mock.Anything, testModeratorID).
+		Return(&model.User{ID: testModeratorID, Role: model.RoleModerator}, nil)
+	mockQuestionRepo.On("Restore", mock.Anything, uint(1), 3).Return(repository.ErrVersionMismatch)
+
+	req := withUser(httptest.NewRequest("POST", "/questions/1/restore", nil), testModeratorID)
+	req.Header.Set("If-Match", `"3"`)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	mockQuestionRepo.AssertExpectations(t)
+	mockQuestionRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestRestoreQuestion_ErrForbidden(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockUserRepo.On("GetByID", mock.Anything, testUserID).
+		Return(&model.User{ID: testUserID, Role: model.RoleUser}, nil)
+
+	req := withUser(httptest.NewRequest("POST", "/questions/1/restore", nil), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockQuestionRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVoteQuestion_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
 
-	expectedQuestion := &model.Question{ID: 1, Text: "Test question"}
-	
-	mockQuestionRepo.On("Create", mock.Anything, mock.MatchedBy(func(q *model.Question) bool {
-		return q.Text == expectedQuestion.Text
-	})).Return(expectedQuestion, nil)
+	mockQuestionRepo.On("Vote", mock.Anything, uint(1), testUserID, 1, 0).Return(5, nil)
 
-	requestBody := map[string]string{"text": "Test question"}
-	body, _ := json.Marshal(requestBody)
+	body, _ := json.Marshal(map[string]int{"value": 1})
 
-	req := httptest.NewRequest("POST", "/questions/", bytes.NewReader(body))
+	req := withUser(httptest.NewRequest("POST", "/questions/1/vote", bytes.NewReader(body)), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, http.StatusOK, rr.Code)
 
-	var response model.Question
+	var response model.VoteSummary
 	err := json.Unmarshal(rr.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedQuestion.ID, response.ID)
-	assert.Equal(t, expectedQuestion.Text, response.Text)
+	assert.Equal(t, model.VoteSummary{Score: 5, Vote: 1}, response)
 
 	mockQuestionRepo.AssertExpectations(t)
 }
 
-func TestCreateQuestion_ErrEmptyText(t *testing.T) {
+func TestVoteQuestion_ErrInvalidVote(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	handler := NewRouter(questionService, nil)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
 
-	requestBody := map[string]string{"text": ""}
-	body, _ := json.Marshal(requestBody)
+	body, _ := json.Marshal(map[string]int{"value": 2})
 
-	req := httptest.NewRequest("POST", "/questions/", bytes.NewReader(body))
+	req := withUser(httptest.NewRequest("POST", "/questions/1/vote", bytes.NewReader(body)), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	assert.Contains(t, rr.Body.String(), service.ErrEmptyText.Error())
+	assert.Contains(t, rr.Body.String(), service.ErrInvalidVote.Error())
+	mockQuestionRepo.AssertNotCalled(t, "Vote")
 }
 
-func TestGetQuestion_Success(t *testing.T) {
+func TestVoteQuestion_ErrUnauthenticated(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	handler := NewRouter(questionService, nil)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
 
-	expectedQuestion := &model.Question{
-		ID:      1,
-		Text:    "Test question",
-		Answers: []model.Answer{},
-	}
-	
-	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
-		Return(expectedQuestion, nil)
+	body, _ := json.Marshal(map[string]int{"value": 1})
 
-	req := httptest.NewRequest("GET", "/questions/1", nil)
+	req := httptest.NewRequest("POST", "/questions/1/vote", bytes.NewReader(body))
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusOK, rr.Code)
-
-	var response model.Question
-	err := json.Unmarshal(rr.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Equal(t, expectedQuestion.ID, response.ID)
-	assert.Equal(t, expectedQuestion.Text, response.Text)
-
-	mockQuestionRepo.AssertExpectations(t)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockQuestionRepo.AssertNotCalled(t, "Vote")
 }
 
-func TestGetQuestion_ErrQuestionNotExists(t *testing.T) {
+func TestVoteQuestion_ErrQuestionNotExists(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	handler := NewRouter(questionService, nil)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
 
-	mockQuestionRepo.On("GetByID", mock.Anything, uint(999)).
-		Return((*model.Question)(nil), repository.ErrQuestionNotFound)
+	mockQuestionRepo.On("Vote", mock.Anything, uint(1), testUserID, -1, 0).Return(0, repository.ErrQuestionNotFound)
 
-	req := httptest.NewRequest("GET", "/questions/999", nil)
+	body, _ := json.Marshal(map[string]int{"value": -1})
+
+	req := withUser(httptest.NewRequest("POST", "/questions/1/vote", bytes.NewReader(body)), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusNotFound, rr.Code)
-	assert.Contains(t, rr.Body.String(), service.ErrQuestionNotExists.Error())
-
 	mockQuestionRepo.AssertExpectations(t)
 }
 
-func TestGetQuestion_ErrInvalidID(t *testing.T) {
+func TestUnvoteQuestion_Success(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	handler := NewRouter(questionService, nil)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
 
-	req := httptest.NewRequest("GET", "/questions/-123", nil)
+	mockQuestionRepo.On("Unvote", mock.Anything, uint(1), testUserID, 0).Return(4, nil)
+
+	req := withUser(httptest.NewRequest("DELETE", "/questions/1/vote", nil), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	assert.Contains(t, rr.Body.String(), "invalid question ID")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.VoteSummary
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, model.VoteSummary{Score: 4}, response)
+
+	mockQuestionRepo.AssertExpectations(t)
 }
 
-func TestGetAllQuestions_Success(t *testing.T) {
+func TestAcceptAnswer_Success(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	handler := NewRouter(questionService, nil)
-
-	expectedQuestions := []model.Question{
-		{ID: 1, Text: "Question 1"},
-		{ID: 2, Text: "Question 2"},
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	acceptedID := uint(3)
+	answers := []model.Answer{
+		{ID: 1, QuestionID: 1, Text: "Top answer", Score: 10},
+		{ID: 2, QuestionID: 1, Text: "Good answer", Score: 5},
+		{ID: 3, QuestionID: 1, Text: "Accepted answer", Score: 1},
 	}
-	mockQuestionRepo.On("GetAll", mock.Anything).Return(expectedQuestions, nil)
 
-	req := httptest.NewRequest("GET", "/questions/", nil)
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question", Answers: answers}, nil).Once()
+	mockQuestionRepo.On("Accept", mock.Anything, uint(1), uint(3), 0).Return(nil)
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question", AcceptedAnswerID: &acceptedID, Answers: answers}, nil).Once()
+
+	req := withUser(httptest.NewRequest("POST", "/questions/1/accept/3", nil), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
-	var response []model.Question
+	var response model.Question
 	err := json.Unmarshal(rr.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Len(t, response, 2)
-	assert.Equal(t, expectedQuestions[0].ID, response[0].ID)
-	assert.Equal(t, expectedQuestions[0].Text, response[0].Text)
+	assert.Equal(t, &acceptedID, response.AcceptedAnswerID)
+	if assert.Len(t, response.Answers, 3) {
+		assert.Equal(t, []uint{3, 1, 2}, []uint{response.Answers[0].ID, response.Answers[1].ID, response.Answers[2].ID})
+	}
 
 	mockQuestionRepo.AssertExpectations(t)
 }
 
-func TestDeleteQuestion_Success(t *testing.T) {
+func TestAcceptAnswer_ErrAnswerNotInQuestion(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	handler := NewRouter(questionService, nil)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
 
-	mockQuestionRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}, nil)
+	mockQuestionRepo.On("Accept", mock.Anything, uint(1), uint(7), 0).Return(repository.ErrAnswerNotFound)
 
-	req := httptest.NewRequest("DELETE", "/questions/1", nil)
+	req := withUser(httptest.NewRequest("POST", "/questions/1/accept/7", nil), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrAnswerNotInQuestion.Error())
 	mockQuestionRepo.AssertExpectations(t)
 }
 
-func TestDeleteQuestion_ErrQuestionNotExists(t *testing.T) {
-	mockQuestionRepo := new(mocks.MockQuestionRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	handler := NewRouter(questionService, nil)
-
-	mockQuestionRepo.On("Delete", mock.Anything, uint(999)).
-		Return(repository.ErrQuestionNotFound)
+func TestAcceptAnswer_ErrNotQuestionAuthor(t *testing.T) {
+	for _, role := range []string{model.RoleUser, model.RoleModerator, model.RoleAdmin} {
+		t.Run(role, func(t *testing.T) {
+			mockQuestionRepo := new(mocks.MockQuestionRepository)
+			mockUserRepo := new(mocks.MockUserRepository)
+			questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+			handler := NewRouter(questionService, nil, nil)
 
-	req := httptest.NewRequest("DELETE", "/questions/999", nil)
-	rr := httptest.NewRecorder()
+			mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+				Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}, nil)
+			mockUserRepo.On("GetByID", mock.Anything, testOtherUserID).
+				Return(&model.User{ID: testOtherUserID, Role: role}, nil)
 
-	handler.ServeHTTP(rr, req)
+			req := withUser(httptest.NewRequest("POST", "/questions/1/accept/3", nil), testOtherUserID)
+			rr := httptest.NewRecorder()
 
-	assert.Equal(t, http.StatusNotFound, rr.Code)
-	assert.Contains(t, rr.Body.String(), service.ErrQuestionNotExists.Error())
+			handler.ServeHTTP(rr, req)
 
-	mockQuestionRepo.AssertExpectations(t)
+			assert.Equal(t, http.StatusForbidden, rr.Code)
+			assert.Contains(t, rr.Body.String(), problemTypeBase+"not-question-author")
+			mockQuestionRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
 }
 
 func TestCreateAnswer_Success(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
 
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
 	question := &model.Question{ID: 1, Text: "Test question"}
 	expectedAnswer := &model.Answer{
@@ -208,17 +911,14 @@ func TestCreateAnswer_Success(t *testing.T) {
 	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).Return(question, nil)
 	mockAnswerRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *model.Answer) bool {
 		return a.QuestionID == 1 && 
-		       a.UserID == "123e4567-e89b-12d3-a456-426614174000" && 
+		       a.UserID == testUserID && 
 		       a.Text == "Test answer"
 	})).Return(expectedAnswer, nil)
 
-	requestBody := map[string]string{
-		"user_id": "123e4567-e89b-12d3-a456-426614174000",
-		"text":    "Test answer",
-	}
+	requestBody := map[string]string{"text": "Test answer"}
 	body, _ := json.Marshal(requestBody)
 
-	req := httptest.NewRequest("POST", "/questions/1/answers/", bytes.NewReader(body))
+	req := withUser(httptest.NewRequest("POST", "/questions/1/answers/", bytes.NewReader(body)), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
@@ -237,19 +937,17 @@ func TestCreateAnswer_Success(t *testing.T) {
 
 func TestCreateAnswer_ErrEmptyText(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
 
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
-	requestBody := map[string]string{
-		"user_id": "123e4567-e89b-12d3-a456-426614174000",
-		"text":    "",
-	}
+	requestBody := map[string]string{"text": ""}
 	body, _ := json.Marshal(requestBody)
 
-	req := httptest.NewRequest("POST", "/questions/1/answers/", bytes.NewReader(body))
+	req := withUser(httptest.NewRequest("POST", "/questions/1/answers/", bytes.NewReader(body)), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
@@ -258,16 +956,17 @@ func TestCreateAnswer_ErrEmptyText(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), service.ErrEmptyText.Error())
 }
 
-func TestCreateAnswer_ErrEmptyUserID(t *testing.T) {
+func TestCreateAnswer_ErrUnauthenticated(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
 
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
 	requestBody := map[string]string{
-		"user_id": "",
+		"user_id": "123e4567-e89b-12d3-a456-426614174000",
 		"text":    "Test answer",
 	}
 	body, _ := json.Marshal(requestBody)
@@ -277,25 +976,25 @@ func TestCreateAnswer_ErrEmptyUserID(t *testing.T) {
 
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	assert.Contains(t, rr.Body.String(), service.ErrEmptyUserID.Error())
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrUnauthenticated.Error())
+
+	mockAnswerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 }
 
 func TestCreateAnswer_ErrInvalidUserID(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
 
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
-	requestBody := map[string]string{
-		"user_id": "invalid-uuid",
-		"text":    "Test answer",
-	}
+	requestBody := map[string]string{"text": "Test answer"}
 	body, _ := json.Marshal(requestBody)
 
-	req := httptest.NewRequest("POST", "/questions/1/answers/", bytes.NewReader(body))
+	req := withUser(httptest.NewRequest("POST", "/questions/1/answers/", bytes.NewReader(body)), "invalid-uuid")
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
@@ -306,22 +1005,20 @@ func TestCreateAnswer_ErrInvalidUserID(t *testing.T) {
 
 func TestCreateAnswer_ErrQuestionNotExists(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
 
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
 	mockQuestionRepo.On("GetByID", mock.Anything, uint(999)).
 		Return((*model.Question)(nil), repository.ErrQuestionNotFound)
 
-	requestBody := map[string]string{
-		"user_id": "123e4567-e89b-12d3-a456-426614174000",
-		"text":    "Test answer",
-	}
+	requestBody := map[string]string{"text": "Test answer"}
 	body, _ := json.Marshal(requestBody)
 
-	req := httptest.NewRequest("POST", "/questions/999/answers/", bytes.NewReader(body))
+	req := withUser(httptest.NewRequest("POST", "/questions/999/answers/", bytes.NewReader(body)), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
@@ -334,11 +1031,12 @@ func TestCreateAnswer_ErrQuestionNotExists(t *testing.T) {
 
 func TestGetAnswer_Success(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
 
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
 	expectedAnswer := &model.Answer{
 		ID:         1,
@@ -367,11 +1065,12 @@ func TestGetAnswer_Success(t *testing.T) {
 
 func TestGetAnswer_ErrAnswerNotExists(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
 
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
 	mockAnswerRepo.On("GetByID", mock.Anything, uint(999)).
 		Return((*model.Answer)(nil), repository.ErrAnswerNotFound)
@@ -389,15 +1088,18 @@ func TestGetAnswer_ErrAnswerNotExists(t *testing.T) {
 
 func TestDeleteAnswer_Success(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
 
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
-	mockAnswerRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+	mockAnswerRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Answer{ID: 1, QuestionID: 1, UserID: testUserID, Text: "Test answer"}, nil)
+	mockAnswerRepo.On("Delete", mock.Anything, uint(1), 0).Return(nil)
 
-	req := httptest.NewRequest("DELETE", "/answers/1", nil)
+	req := withUser(httptest.NewRequest("DELETE", "/answers/1", nil), testUserID)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
@@ -406,17 +1108,177 @@ func TestDeleteAnswer_Success(t *testing.T) {
 	mockAnswerRepo.AssertExpectations(t)
 }
 
+func TestDeleteAnswer_ErrForbidden(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAnswerRepo := new(mocks.MockAnswerRepository)
+
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
+
+	mockAnswerRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Answer{ID: 1, QuestionID: 1, UserID: testUserID, Text: "Test answer"}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, testOtherUserID).
+		Return(&model.User{ID: testOtherUserID, Role: model.RoleUser}, nil)
+
+	req := withUser(httptest.NewRequest("DELETE", "/answers/1", nil), testOtherUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrForbidden.Error())
+
+	mockAnswerRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestDeleteAnswer_ErrAnswerNotExists(t *testing.T) {
 	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAnswerRepo := new(mocks.MockAnswerRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
+
+	mockAnswerRepo.On("GetByID", mock.Anything, uint(999)).
+		Return((*model.Answer)(nil), repository.ErrAnswerNotFound)
+
+	req := withUser(httptest.NewRequest("DELETE", "/answers/999", nil), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrAnswerNotExists.Error())
+
+	mockAnswerRepo.AssertExpectations(t)
+}
+
+func TestRestoreAnswer_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAnswerRepo := new(mocks.MockAnswerRepository)
+
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
+
+	expectedAnswer := &model.Answer{ID: 1, QuestionID: 1, Text: "Test answer"}
+
+	mockUserRepo.On("GetByID", mock.Anything, testModeratorID).
+		Return(&model.User{ID: testModeratorID, Role: model.RoleModerator}, nil)
+	mockAnswerRepo.On("Restore", mock.Anything, uint(1), 0).Return(nil)
+	mockAnswerRepo.On("GetByID", mock.Anything, uint(1)).Return(expectedAnswer, nil)
+
+	req := withUser(httptest.NewRequest("POST", "/answers/1/restore", nil), testModeratorID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.Answer
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedAnswer.ID, response.ID)
+
+	mockAnswerRepo.AssertExpectations(t)
+}
+
+func TestRestoreAnswer_ErrQuestionNotExists(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAnswerRepo := new(mocks.MockAnswerRepository)
+
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
+
+	mockUserRepo.On("GetByID", mock.Anything, testModeratorID).
+		Return(&model.User{ID: testModeratorID, Role: model.RoleModerator}, nil)
+	mockAnswerRepo.On("Restore", mock.Anything, uint(1), 0).Return(repository.ErrQuestionNotFound)
+
+	req := withUser(httptest.NewRequest("POST", "/answers/1/restore", nil), testModeratorID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrQuestionNotExists.Error())
+
+	mockAnswerRepo.AssertExpectations(t)
+}
+
+func TestUpdateAnswer_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAnswerRepo := new(mocks.MockAnswerRepository)
+
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
+
+	expectedAnswer := &model.Answer{ID: 1, QuestionID: 1, UserID: testUserID, Text: "Edited answer"}
+
+	mockAnswerRepo.On("GetByID", mock.Anything, uint(1)).
+		Return(&model.Answer{ID: 1, QuestionID: 1, UserID: testUserID, Text: "Test answer"}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, testModeratorID).
+		Return(&model.User{ID: testModeratorID, Role: model.RoleAdmin}, nil)
+	mockAnswerRepo.On("Update", mock.Anything, uint(1), "Edited answer", testModeratorID, 0).Return(expectedAnswer, nil)
+
+	requestBody := map[string]string{"text": "Edited answer"}
+	body, _ := json.Marshal(requestBody)
+
+	req := withUser(httptest.NewRequest("PATCH", "/answers/1", bytes.NewReader(body)), testModeratorID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.Answer
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedAnswer.Text, response.Text)
+
+	mockAnswerRepo.AssertExpectations(t)
+}
+
+func TestUpdateAnswer_ErrEmptyText(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAnswerRepo := new(mocks.MockAnswerRepository)
+
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
+
+	requestBody := map[string]string{"text": ""}
+	body, _ := json.Marshal(requestBody)
+
+	req := withUser(httptest.NewRequest("PATCH", "/answers/1", bytes.NewReader(body)), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrEmptyText.Error())
+}
+
+func TestGetAnswerRevisions_ErrAnswerNotExists(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
 	mockAnswerRepo := new(mocks.MockAnswerRepository)
-	questionService := service.NewQuestionService(mockQuestionRepo)
-	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo)
-	handler := NewRouter(questionService, answerService)
 
-	mockAnswerRepo.On("Delete", mock.Anything, uint(999)).
-		Return(repository.ErrAnswerNotFound)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, answerService, nil)
 
-	req := httptest.NewRequest("DELETE", "/answers/999", nil)
+	mockAnswerRepo.On("GetByID", mock.Anything, uint(999)).
+		Return((*model.Answer)(nil), repository.ErrAnswerNotFound)
+
+	req := httptest.NewRequest("GET", "/answers/999/revisions", nil)
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
@@ -425,4 +1287,90 @@ func TestDeleteAnswer_ErrAnswerNotExists(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), service.ErrAnswerNotExists.Error())
 
 	mockAnswerRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestVoteAnswer_Success(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockAnswerRepo := new(mocks.MockAnswerRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(nil, answerService, nil)
+
+	mockAnswerRepo.On("Vote", mock.Anything, uint(1), testUserID, -1, 0).Return(-1, nil)
+
+	body, _ := json.Marshal(map[string]int{"value": -1})
+
+	req := withUser(httptest.NewRequest("POST", "/answers/1/vote", bytes.NewReader(body)), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.VoteSummary
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, model.VoteSummary{Score: -1, Vote: -1}, response)
+
+	mockAnswerRepo.AssertExpectations(t)
+}
+
+func TestUnvoteAnswer_ErrAnswerNotExists(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockAnswerRepo := new(mocks.MockAnswerRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	answerService := service.NewAnswerService(mockAnswerRepo, mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(nil, answerService, nil)
+
+	mockAnswerRepo.On("Unvote", mock.Anything, uint(1), testUserID, 0).Return(0, repository.ErrAnswerNotFound)
+
+	req := withUser(httptest.NewRequest("DELETE", "/answers/1/vote", nil), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockAnswerRepo.AssertExpectations(t)
+}
+
+func TestSearch_Success(t *testing.T) {
+	mockSearchRepo := new(mocks.MockSearchRepository)
+	searchService := service.NewSearchService(mockSearchRepo)
+	handler := NewRouter(nil, nil, searchService)
+
+	expectedHits := []model.SearchHit{
+		{Type: model.SearchHitQuestion, ID: 1, QuestionID: 1, Rank: 0.6, Snippet: "How to <mark>index</mark> jsonb?"},
+		{Type: model.SearchHitAnswer, ID: 7, QuestionID: 1, Rank: 0.3, Snippet: "Use a GIN <mark>index</mark>"},
+	}
+	mockSearchRepo.On("Search", mock.Anything, "index", service.DefaultPageSize).Return(expectedHits, nil)
+
+	req := httptest.NewRequest("GET", "/search?q=+index+", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.SearchResult
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedHits, response.Hits)
+
+	mockSearchRepo.AssertExpectations(t)
+}
+
+func TestSearch_ErrEmptyQuery(t *testing.T) {
+	mockSearchRepo := new(mocks.MockSearchRepository)
+	searchService := service.NewSearchService(mockSearchRepo)
+	handler := NewRouter(nil, nil, searchService)
+
+	req := httptest.NewRequest("GET", "/search?q=", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), service.ErrEmptyQuery.Error())
+
+	mockSearchRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+}
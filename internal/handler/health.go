@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/service"
+)
+
+// RegisterHealth registers the liveness probe GET /healthz and the readiness probe GET /readyz on mux.
+func RegisterHealth(mux *http.ServeMux, healthService service.HealthService) {
+	mux.HandleFunc("GET /healthz", healthz())
+	mux.HandleFunc("GET /readyz", readyz(healthService))
+}
+
+// healthz reports that the process is alive without touching any dependency.
+func healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(model.HealthReport{Status: model.HealthOK})
+	}
+}
+
+func readyz(svc service.HealthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := svc.Ready(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status != model.HealthOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	}
+}
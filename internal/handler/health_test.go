@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveHealth(t *testing.T, healthService service.HealthService, target string) (int, model.HealthReport) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	RegisterHealth(mux, healthService)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+
+	var report model.HealthReport
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	return rr.Code, report
+}
+
+func passingCheck(name string) service.ReadinessCheck {
+	return service.ReadinessCheck{Name: name, Check: func(ctx context.Context) error { return nil }}
+}
+
+func TestHealthz(t *testing.T) {
+	failing := service.ReadinessCheck{Name: "database", Check: func(ctx context.Context) error { return errors.New("down") }}
+	healthService := service.NewHealthService(time.Second, failing)
+
+	code, report := serveHealth(t, healthService, "/healthz")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, model.HealthOK, report.Status)
+}
+
+func TestReadyz_Ready(t *testing.T) {
+	healthService := service.NewHealthService(time.Second, passingCheck("database"), passingCheck("migrations"))
+
+	code, report := serveHealth(t, healthService, "/readyz")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, model.HealthOK, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "database", report.Checks[0].Name)
+	assert.Equal(t, model.HealthOK, report.Checks[0].Status)
+	assert.Equal(t, "migrations", report.Checks[1].Name)
+	assert.Equal(t, model.HealthOK, report.Checks[1].Status)
+}
+
+func TestReadyz_FailingCheck(t *testing.T) {
+	failing := service.ReadinessCheck{Name: "migrations", Check: func(ctx context.Context) error {
+		return errors.New("database schema is behind the expected version")
+	}}
+	healthService := service.NewHealthService(time.Second, passingCheck("database"), failing)
+
+	code, report := serveHealth(t, healthService, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, model.HealthUnavailable, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, model.HealthOK, report.Checks[0].Status)
+	assert.Equal(t, model.HealthUnavailable, report.Checks[1].Status)
+	assert.Equal(t, "database schema is behind the expected version", report.Checks[1].Error)
+}
+
+func TestReadyz_CheckTimesOut(t *testing.T) {
+	hanging := service.ReadinessCheck{Name: "database", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	healthService := service.NewHealthService(10*time.Millisecond, hanging)
+
+	code, report := serveHealth(t, healthService, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	require.Len(t, report.Checks, 1)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+func TestReadyz_Draining(t *testing.T) {
+	healthService := service.NewHealthService(time.Second, passingCheck("database"))
+	healthService.Drain()
+
+	code, report := serveHealth(t, healthService, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, model.HealthUnavailable, report.Status)
+	require.Len(t, report.Checks, 1)
+	assert.Equal(t, "shutdown", report.Checks[0].Name)
+	assert.Equal(t, service.ErrShuttingDown.Error(), report.Checks[0].Error)
+
+	code, _ = serveHealth(t, healthService, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/ppb03/qna-api/internal/service"
+)
+
+// idempotencyKeyHeader carries the key a client chooses for a request to make its retries safe.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotentReplayedHeader marks a response replayed for a retried request.
+const idempotentReplayedHeader = "Idempotent-Replayed"
+
+// replayedHeaders lists the headers of the 201 response which are stored along with its body for replay, if set.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// idempotentRoutes lists the routes whose requests IdempotencyMiddleware makes safe to retry.
+var idempotentRoutes = map[string]bool{
+	"POST /questions/":              true,
+	"POST /questions/{id}/answers/": true,
+}
+
+// IdempotencyMiddleware makes retries of requests to idempotentRoutes which carry the Idempotency-Key header safe.
+// The 201 response to the first request is stored and replayed for retries with the same key and body,
+// while any other response frees the key, so that the request can be retried. It has to run after AuthMiddleware
+// since keys are scoped to users.
+func IdempotencyMiddleware(router *http.ServeMux, svc service.IdempotencyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if key == "" || !idempotentRoutes[routePattern(router, r)] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := readBody(w, r)
+			if err != nil {
+				writeProblem(w, r, err)
+				return
+			}
+
+			record, err := svc.Begin(r.Context(), key, requestHash(r, body))
+			if err != nil {
+				writeProblem(w, r, err)
+				return
+			}
+			if record != nil {
+				// Records stored before headers were kept have none, but all of them are JSON.
+				w.Header().Set("Content-Type", "application/json")
+				for name, value := range record.Headers {
+					w.Header().Set(name, value)
+				}
+				w.Header().Set(idempotentReplayedHeader, "true")
+				w.WriteHeader(record.StatusCode)
+				w.Write(record.Body)
+				return
+			}
+
+			capture := &responseCapture{statusRecorder: statusRecorder{ResponseWriter: w, status: http.StatusOK}}
+			next.ServeHTTP(capture, r)
+
+			// The outcome is saved even if the client has gone away, since that is when it is going to retry.
+			// Failures are logged by the service and do not affect the response which has already been sent.
+			ctx := context.WithoutCancel(r.Context())
+			if capture.status == http.StatusCreated {
+				svc.Complete(ctx, key, capture.status, storedHeaders(capture.Header()), capture.body.Bytes())
+			} else {
+				svc.Release(ctx, key)
+			}
+		})
+	}
+}
+
+// storedHeaders picks replayedHeaders out of the headers of a response.
+func storedHeaders(header http.Header) map[string]string {
+	stored := make(map[string]string)
+	for _, name := range replayedHeaders {
+		if value := header.Get(name); value != "" {
+			stored[name] = value
+		}
+	}
+	return stored
+}
+
+// requestHash identifies a request by its method, path and body, so that a key reused for another request is detected.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseCapture keeps a copy of the response body written through it.
+type responseCapture struct {
+	statusRecorder
+	body bytes.Buffer
+}
+
+func (rc *responseCapture) Write(b []byte) (int, error) {
+	rc.body.Write(b)
+	return rc.statusRecorder.Write(b)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type idempotencyFixture struct {
+	handler     http.Handler
+	questions   repository.QuestionRepository
+	idempotency repository.IdempotencyRepository
+}
+
+func newIdempotencyFixture() idempotencyFixture {
+	router, backend := newMemoryRouter(repository.NewMemoryUserRepository())
+	idempotency := repository.NewMemoryIdempotencyRepository(backend.store)
+
+	idempotencyService := service.NewIdempotencyService(idempotency, time.Hour)
+	handler := AuthMiddleware(auth.NewVerifier(testSigningKey))(IdempotencyMiddleware(router, idempotencyService)(router))
+	return idempotencyFixture{handler: handler, questions: backend.questions, idempotency: idempotency}
+}
+
+func (f idempotencyFixture) post(t *testing.T, userID, path, key, body string) *httptest.ResponseRecorder {
+	token, err := auth.NewToken(testSigningKey, userID, time.Minute)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	rr := httptest.NewRecorder()
+	f.handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func (f idempotencyFixture) countQuestions(t *testing.T) int {
+	questions, err := f.questions.GetAll(context.Background(), repository.QuestionQuery{Limit: 100})
+	require.NoError(t, err)
+	return len(questions)
+}
+
+func problemTypeOf(t *testing.T, rr *httptest.ResponseRecorder) string {
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	return problem.Type
+}
+
+func TestIdempotency_ReplaysCreatedResponse(t *testing.T) {
+	f := newIdempotencyFixture()
+
+	first := f.post(t, testUserID, "/questions/", "key-1", `{"text": "Test question"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+
+	retry := f.post(t, testUserID, "/questions/", "key-1", `{"text": "Test question"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, `"1"`, retry.Header().Get("ETag"))
+	assert.Equal(t, "/questions/1", retry.Header().Get("Location"))
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, 1, f.countQuestions(t))
+
+	other := f.post(t, testOtherUserID, "/questions/", "key-1", `{"text": "Test question"}`)
+	assert.Equal(t, http.StatusCreated, other.Code, "keys are scoped to users")
+	assert.Empty(t, other.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 2, f.countQuestions(t))
+
+	f.post(t, testUserID, "/questions/", "", `{"text": "Test question"}`)
+	assert.Equal(t, 3, f.countQuestions(t), "requests without a key are not deduplicated")
+}
+
+func TestIdempotency_ReplaysCreatedAnswer(t *testing.T) {
+	f := newIdempotencyFixture()
+
+	question := f.post(t, testUserID, "/questions/", "", `{"text": "Test question"}`)
+	require.Equal(t, http.StatusCreated, question.Code)
+
+	first := f.post(t, testUserID, "/questions/1/answers/", "answer-key", `{"text": "Test answer"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	retry := f.post(t, testUserID, "/questions/1/answers/", "answer-key", `{"text": "Test answer"}`)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, first.Header().Get("ETag"), retry.Header().Get("ETag"))
+	assert.Equal(t, "/answers/1", retry.Header().Get("Location"))
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+
+	stored, err := f.questions.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, stored.Answers, 1)
+}
+
+func TestIdempotency_KeyReusedWithDifferentRequest(t *testing.T) {
+	f := newIdempotencyFixture()
+
+	require.Equal(t, http.StatusCreated, f.post(t, testUserID, "/questions/", "key-1", `{"text": "Test question"}`).Code)
+
+	cases := map[string]struct {
+		path string
+		body string
+	}{
+		"different body": {"/questions/", `{"text": "Another question"}`},
+		"different path": {"/questions/1/answers/", `{"text": "Test question"}`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			rr := f.post(t, testUserID, c.path, "key-1", c.body)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			assert.Equal(t, "urn:qna-api:problem:idempotency-key-reused", problemTypeOf(t, rr))
+		})
+	}
+	assert.Equal(t, 1, f.countQuestions(t))
+}
+
+func TestIdempotency_FailedRequestReleasesKey(t *testing.T) {
+	f := newIdempotencyFixture()
+
+	failed := f.post(t, testUserID, "/questions/", "key-1", `{"text": ""}`)
+	require.Equal(t, http.StatusBadRequest, failed.Code)
+
+	rr := f.post(t, testUserID, "/questions/", "key-1", `{"text": "Fixed question"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_KeyInProgress(t *testing.T) {
+	f := newIdempotencyFixture()
+
+	body := `{"text": "Test question"}`
+	_, err := f.idempotency.Create(context.Background(), &model.IdempotencyRecord{
+		UserID:      testUserID,
+		Key:         "key-1",
+		RequestHash: requestHash(httptest.NewRequest("POST", "/questions/", nil), []byte(body)),
+		ExpiresAt:   time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	rr := f.post(t, testUserID, "/questions/", "key-1", body)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "urn:qna-api:problem:idempotency-key-in-progress", problemTypeOf(t, rr))
+	assert.Zero(t, f.countQuestions(t))
+}
+
+func TestIdempotency_BodyTooLarge(t *testing.T) {
+	f := newIdempotencyFixture()
+
+	rr := f.post(t, testUserID, "/questions/", "key-1", `{"text": "`+strings.Repeat("a", maxBodySize)+`"}`)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Equal(t, "urn:qna-api:problem:body-too-large", problemTypeOf(t, rr))
+	assert.Zero(t, f.countQuestions(t))
+
+	rr = f.post(t, testUserID, "/questions/", "key-1", `{"text": "Test question"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code, "the key is not taken by a rejected request")
+}
+
+func TestIdempotency_InvalidKey(t *testing.T) {
+	f := newIdempotencyFixture()
+
+	rr := f.post(t, testUserID, "/questions/", strings.Repeat("k", 256), `{"text": "Test question"}`)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "urn:qna-api:problem:invalid-idempotency-key", problemTypeOf(t, rr))
+}
+
+func TestIdempotency_RequiresAuthentication(t *testing.T) {
+	f := newIdempotencyFixture()
+
+	req := httptest.NewRequest("POST", "/questions/", strings.NewReader(`{"text": "Test question"}`))
+	req.Header.Set("Idempotency-Key", "key-1")
+	rr := httptest.NewRecorder()
+	f.handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
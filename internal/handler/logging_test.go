@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/mocks"
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs makes the default logger write JSON lines into the returned buffer until the test ends.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// logLines decodes the captured JSON lines.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func findLine(lines []map[string]any, msg string) map[string]any {
+	for _, line := range lines {
+		if line["msg"] == msg {
+			return line
+		}
+	}
+	return nil
+}
+
+func newLoggedHandler(questionService service.QuestionService) http.Handler {
+	router := NewRouter(questionService, nil, nil)
+	return RequestIDMiddleware(LoggingMiddleware(AuthMiddleware(auth.NewVerifier(testSigningKey))(router)))
+}
+
+func TestRequestIDMiddleware_HonorsValidHeader(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	handler := newLoggedHandler(service.NewQuestionService(mockQuestionRepo, mockUserRepo))
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(999)).
+		Return((*model.Question)(nil), errors.New("unexpected"))
+
+	req := httptest.NewRequest("GET", "/questions/999", nil)
+	req.Header.Set("X-Request-ID", "client-request-id")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, "client-request-id", rr.Header().Get("X-Request-ID"))
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	assert.Equal(t, "client-request-id", problem.RequestID)
+}
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	cases := map[string]string{
+		"missing":           "",
+		"too long":          strings.Repeat("a", maxRequestIDLength+1),
+		"control character": "id\twith\ttabs",
+		"non-ascii":         "идентификатор",
+	}
+
+	for name, header := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(requestID(r)))
+			}))
+
+			req := httptest.NewRequest("GET", "/questions/", nil)
+			if header != "" {
+				req.Header.Set("X-Request-ID", header)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			id := rr.Header().Get("X-Request-ID")
+			assert.Len(t, id, 32)
+			assert.NotEqual(t, header, id)
+			assert.Equal(t, id, rr.Body.String())
+		})
+	}
+}
+
+func TestLoggingMiddleware_CompletionLine(t *testing.T) {
+	logs := captureLogs(t)
+
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	handler := newLoggedHandler(service.NewQuestionService(mockQuestionRepo, mockUserRepo))
+
+	mockQuestionRepo.On("Create", mock.Anything, mock.Anything).
+		Return(&model.Question{ID: 1, AuthorID: testUserID, Text: "Test question"}, nil)
+
+	token, err := auth.NewToken(testSigningKey, testUserID, time.Minute)
+	require.NoError(t, err)
+
+	body, _ := json.Marshal(map[string]string{"text": "Test question"})
+	req := httptest.NewRequest("POST", "/questions/", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Request-ID", "logged-request-id")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	lines := logLines(t, logs)
+	started := findLine(lines, "request started")
+	require.NotNil(t, started)
+	assert.Equal(t, "logged-request-id", started["request_id"])
+
+	completed := findLine(lines, "request completed")
+	require.NotNil(t, completed)
+	assert.Equal(t, "logged-request-id", completed["request_id"])
+	assert.Equal(t, float64(http.StatusCreated), completed["status"])
+	assert.Equal(t, float64(rr.Body.Len()), completed["bytes"])
+	assert.Equal(t, testUserID, completed["user_id"])
+}
+
+func TestLoggingMiddleware_ServiceErrorIsCorrelated(t *testing.T) {
+	logs := captureLogs(t)
+
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	handler := newLoggedHandler(service.NewQuestionService(mockQuestionRepo, mockUserRepo))
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return((*model.Question)(nil), errors.New("connection refused"))
+
+	req := httptest.NewRequest("GET", "/questions/1", nil)
+	req.Header.Set("X-Request-ID", "failed-request-id")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	lines := logLines(t, logs)
+	serviceLine := findLine(lines, "unexpected internal error")
+	require.NotNil(t, serviceLine)
+	assert.Equal(t, "failed-request-id", serviceLine["request_id"])
+	assert.Contains(t, serviceLine["error"], "connection refused")
+
+	failed := findLine(lines, "request failed")
+	require.NotNil(t, failed)
+	assert.Equal(t, "failed-request-id", failed["request_id"])
+}
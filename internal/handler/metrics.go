@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// RegisterMetrics registers GET /metrics serving the metrics in the Prometheus text format on mux.
+func RegisterMetrics(mux *http.ServeMux) {
+	mux.Handle("GET /metrics", promhttp.Handler())
+}
+
+// MetricsMiddleware counts requests and observes their latency labelled by the pattern of the route in router
+// which serves them, e.g. "GET /questions/{id}", so that the number of series does not grow with the IDs in paths.
+func MetricsMiddleware(router *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := routePattern(router, r)
+
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			metrics.HTTPDuration.WithLabelValues(route).Observe(time.Since(start).Seconds())
+			metrics.HTTPRequests.WithLabelValues(route, strconv.Itoa(recorder.status)).Inc()
+			if recorder.status >= http.StatusInternalServerError {
+				metrics.HTTPErrors.WithLabelValues(route).Inc()
+			}
+		})
+	}
+}
+
+// routePattern returns the pattern of the route in router which serves r or metrics.UnmatchedRoute.
+// It is looked up beforehand rather than read from r.Pattern because middleware between here and the router replaces r.
+func routePattern(router *http.ServeMux, r *http.Request) string {
+	if _, pattern := router.Handler(r); pattern != "" {
+		return pattern
+	}
+	return metrics.UnmatchedRoute
+}
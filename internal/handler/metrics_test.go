@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ppb03/qna-api/internal/metrics"
+	"github.com/ppb03/qna-api/internal/repository"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddleware_LabelsByRoutePattern(t *testing.T) {
+	store := repository.NewMemoryStore()
+	router := newRouter(
+		repository.NewMemoryQuestionRepository(store),
+		repository.NewMemoryAnswerRepository(store),
+		repository.NewMemorySearchRepository(store),
+		repository.NewMemoryUserRepository(),
+	)
+	RegisterMetrics(router)
+	handler := MetricsMiddleware(router)(router)
+
+	const route = "GET /questions/{id}"
+	found := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(route, "200"))
+	notFound := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(route, "404"))
+	unmatched := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(metrics.UnmatchedRoute, "404"))
+	created := testutil.ToFloat64(metrics.QuestionsCreated)
+
+	var question struct{ ID uint }
+	assert.Equal(t, http.StatusCreated, serveJSON(t, handler, "POST", "/questions/", map[string]string{"text": "Question"}, &question))
+	serveJSON(t, handler, "GET", "/questions/1", nil, nil)
+	serveJSON(t, handler, "GET", "/questions/2", nil, nil)
+	serveJSON(t, handler, "GET", "/questions/3", nil, nil)
+	serveJSON(t, handler, "GET", "/nowhere", nil, nil)
+
+	assert.Equal(t, found+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(route, "200")))
+	assert.Equal(t, notFound+2, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(route, "404")))
+	assert.Equal(t, unmatched+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(metrics.UnmatchedRoute, "404")))
+	assert.Equal(t, created+1, testutil.ToFloat64(metrics.QuestionsCreated))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `qna_http_request_duration_seconds_count{route="GET /questions/{id}"}`)
+	assert.False(t, strings.Contains(rr.Body.String(), `route="/questions/1"`))
+}
+
+func TestMetricsMiddleware_CountsServerErrors(t *testing.T) {
+	router := http.NewServeMux()
+	router.HandleFunc("POST /broken/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := MetricsMiddleware(router)(router)
+
+	const route = "POST /broken/{id}"
+	errors := testutil.ToFloat64(metrics.HTTPErrors.WithLabelValues(route))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/broken/7", nil))
+
+	assert.Equal(t, errors+1, testutil.ToFloat64(metrics.HTTPErrors.WithLabelValues(route)))
+}
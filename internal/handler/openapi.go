@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/openapi"
+	"github.com/ppb03/qna-api/internal/service"
+)
+
+// apiSpec returns the OpenAPI document describing the routes registered by NewRouter. It is built once.
+var apiSpec = sync.OnceValue(newAPISpec)
+
+// RegisterOpenAPI registers GET /openapi.json serving the OpenAPI document of the routes registered by NewRouter on mux.
+func RegisterOpenAPI(mux *http.ServeMux) {
+	mux.HandleFunc("GET /openapi.json", openAPIDocument())
+}
+
+func openAPIDocument() http.HandlerFunc {
+	document, err := json.Marshal(apiSpec())
+	if err != nil {
+		panic(fmt.Sprintf("failed to encode the OpenAPI document: %v", err))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(document)
+	}
+}
+
+// ValidationMiddleware rejects requests to the routes of router whose JSON bodies do not match the schemas
+// in the OpenAPI document, so that handlers see only bodies the document allows, unknown properties included.
+func ValidationMiddleware(router *http.ServeMux) func(http.Handler) http.Handler {
+	spec := apiSpec()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op := spec.Operation(routePattern(router, r))
+			if op == nil || op.RequestBody == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := readBody(w, r)
+			if err != nil {
+				writeProblem(w, r, err)
+				return
+			}
+			var value any
+			if err := json.Unmarshal(body, &value); err != nil {
+				writeProblem(w, r, malformedBody(err))
+				return
+			}
+			if err := spec.Validate(op.RequestBody.Content["application/json"].Schema, value); err != nil {
+				writeProblem(w, r, fmt.Errorf("%w: %v", errInvalidBody, err))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Parameters shared by operations.
+var (
+	questionIDParam = &openapi.Parameter{Name: "id", In: "path", Required: true, Description: "ID of the question", Schema: idSchema()}
+	answerIDParam   = &openapi.Parameter{Name: "id", In: "path", Required: true, Description: "ID of the answer", Schema: idSchema()}
+	ifMatchParam    = &openapi.Parameter{
+		Name: "If-Match", In: "header", Schema: &openapi.Schema{Type: "string"},
+		Description: "ETag of the version the change is based on, or * for any version",
+	}
+	ifNoneMatchParam = &openapi.Parameter{
+		Name: "If-None-Match", In: "header", Schema: &openapi.Schema{Type: "string"},
+		Description: "ETags of the versions the client has, the response is 304 if one of them is current",
+	}
+	idempotencyKeyParam = &openapi.Parameter{
+		Name: "Idempotency-Key", In: "header", Schema: &openapi.Schema{Type: "string", MinLength: openapi.Ptr(1), MaxLength: openapi.Ptr(255)},
+		Description: "Key unique to the operation, its retries with the same body replay the first response",
+	}
+	limitParam = &openapi.Parameter{
+		Name: "limit", In: "query", Description: "Maximal number of items to return",
+		Schema: &openapi.Schema{Type: "integer", Minimum: openapi.Ptr(1.0)},
+	}
+)
+
+func idSchema() *openapi.Schema {
+	return &openapi.Schema{Type: "integer", Minimum: openapi.Ptr(1.0)}
+}
+
+// authenticated requires a bearer token, whereas other operations accept anonymous requests.
+var authenticated = []openapi.SecurityRequirement{{"bearerAuth": {}}}
+
+// newAPISpec describes every route registered by NewRouter. The responses of each operation are derived
+// from its successful response and the errors its handler may respond with, see problemResponses.
+func newAPISpec() *openapi.Document {
+	doc := openapi.New("Q&A API", "1.0.0", "API of questions and answers to them.")
+	doc.Security = []openapi.SecurityRequirement{{}, {"bearerAuth": {}}}
+	doc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+	}
+
+	doc.Define(model.Tag{}, &openapi.Schema{Type: "string", Description: "Name of a tag"})
+	question := doc.Schema(model.Question{})
+	answer := doc.Schema(model.Answer{})
+	revisions := doc.Schema([]model.Revision{})
+	summary := doc.Schema(model.VoteSummary{})
+	doc.Schema(Problem{})
+
+	text := &openapi.Schema{Type: "string", MinLength: openapi.Ptr(1)}
+	textBody := object(map[string]*openapi.Schema{"text": text}, "text")
+	voteBody := object(map[string]*openapi.Schema{
+		"value": {Type: "integer", Enum: []any{1, -1}, Description: "1 for an upvote, -1 for a downvote"},
+	}, "value")
+
+	doc.Add("POST /questions/", &openapi.Operation{
+		OperationID: "createQuestion", Summary: "Create a question", Tags: []string{"questions"}, Security: authenticated,
+		Parameters: []*openapi.Parameter{idempotencyKeyParam},
+		RequestBody: jsonBody(object(map[string]*openapi.Schema{
+			"text": text,
+			"tags": {Type: "array", Items: &openapi.Schema{Type: "string"}, Description: "Tags, normalized and deduplicated"},
+		}, "text")),
+		Responses: problemResponses(withLocation(withETag(jsonResponse(http.StatusCreated, "Created question", question))),
+			errMalformedBody, errInvalidBody, service.ErrEmptyText, service.ErrInvalidTag, service.ErrInvalidUserID,
+			service.ErrInvalidIdempotencyKey, service.ErrUnauthenticated,
+			errBodyTooLarge, service.ErrIdempotencyKeyInProgress, service.ErrIdempotencyKeyReused),
+	})
+	doc.Add("DELETE /questions/{id}", &openapi.Operation{
+		OperationID: "deleteQuestion", Summary: "Delete a question with its answers", Tags: []string{"questions"}, Security: authenticated,
+		Parameters: []*openapi.Parameter{questionIDParam, ifMatchParam},
+		Responses: problemResponses(noContent("Question deleted"),
+			errInvalidQuestionID, errMultipleEntityTags, service.ErrInvalidUserID, service.ErrUnauthenticated, service.ErrForbidden,
+			service.ErrQuestionNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("POST /questions/{id}/restore", &openapi.Operation{
+		OperationID: "restoreQuestion", Summary: "Restore a deleted question", Tags: []string{"questions"}, Security: authenticated,
+		Parameters: []*openapi.Parameter{questionIDParam, ifMatchParam},
+		Responses: problemResponses(withETag(jsonResponse(http.StatusOK, "Restored question", question)),
+			errInvalidQuestionID, errMultipleEntityTags, service.ErrInvalidUserID, service.ErrUnauthenticated, service.ErrForbidden,
+			service.ErrQuestionNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("GET /questions/{id}", &openapi.Operation{
+		OperationID: "getQuestion", Summary: "Get a question with its answers", Tags: []string{"questions"},
+		Parameters: []*openapi.Parameter{questionIDParam, ifNoneMatchParam},
+		Responses: problemResponses(withETag(jsonResponse(http.StatusOK, "Question", question)),
+			errInvalidQuestionID, service.ErrQuestionNotExists),
+	})
+	addNotModified(doc.Operation("GET /questions/{id}"))
+	doc.Add("PATCH /questions/{id}", &openapi.Operation{
+		OperationID: "updateQuestion", Summary: "Edit the text of a question", Tags: []string{"questions"}, Security: authenticated,
+		Parameters:  []*openapi.Parameter{questionIDParam, ifMatchParam},
+		RequestBody: jsonBody(textBody),
+		Responses: problemResponses(withETag(jsonResponse(http.StatusOK, "Updated question", question)),
+			errInvalidQuestionID, errMalformedBody, errInvalidBody, errBodyTooLarge, errMultipleEntityTags, service.ErrEmptyText, service.ErrInvalidUserID,
+			service.ErrUnauthenticated, service.ErrForbidden, service.ErrQuestionNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("GET /questions/{id}/revisions", &openapi.Operation{
+		OperationID: "getQuestionRevisions", Summary: "List prior versions of the text of a question", Tags: []string{"questions"},
+		Parameters: []*openapi.Parameter{questionIDParam},
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Revisions of the text", revisions),
+			errInvalidQuestionID, service.ErrQuestionNotExists),
+	})
+	doc.Add("POST /questions/{id}/vote", &openapi.Operation{
+		OperationID: "voteQuestion", Summary: "Vote for a question", Tags: []string{"questions"}, Security: authenticated,
+		Parameters:  []*openapi.Parameter{questionIDParam, ifMatchParam},
+		RequestBody: jsonBody(voteBody),
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Score of the question", summary),
+			errInvalidQuestionID, errMalformedBody, errInvalidBody, errBodyTooLarge, errMultipleEntityTags, service.ErrInvalidVote, service.ErrInvalidUserID,
+			service.ErrUnauthenticated, service.ErrQuestionNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("DELETE /questions/{id}/vote", &openapi.Operation{
+		OperationID: "unvoteQuestion", Summary: "Withdraw the vote for a question", Tags: []string{"questions"}, Security: authenticated,
+		Parameters: []*openapi.Parameter{questionIDParam, ifMatchParam},
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Score of the question", summary),
+			errInvalidQuestionID, errMultipleEntityTags, service.ErrInvalidUserID, service.ErrUnauthenticated,
+			service.ErrQuestionNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("POST /questions/{id}/accept/{answerID}", &openapi.Operation{
+		OperationID: "acceptAnswer", Summary: "Mark an answer as the solution of a question", Tags: []string{"questions"}, Security: authenticated,
+		Parameters: []*openapi.Parameter{
+			questionIDParam,
+			{Name: "answerID", In: "path", Required: true, Description: "ID of the answer", Schema: idSchema()},
+			ifMatchParam,
+		},
+		Responses: problemResponses(withETag(jsonResponse(http.StatusOK, "Question with the accepted answer", question)),
+			errInvalidQuestionID, errInvalidAnswerID, errMultipleEntityTags, service.ErrAnswerNotInQuestion, service.ErrInvalidUserID,
+			service.ErrUnauthenticated, service.ErrNotQuestionAuthor, service.ErrQuestionNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("GET /questions/", &openapi.Operation{
+		OperationID: "listQuestions", Summary: "List questions, oldest first", Tags: []string{"questions"},
+		Parameters: []*openapi.Parameter{
+			{Name: "cursor", In: "query", Description: "Cursor of the page returned as next_cursor", Schema: &openapi.Schema{Type: "string"}},
+			limitParam,
+			{Name: "tag", In: "query", Description: "Tags to filter questions by", Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}},
+			{Name: "match", In: "query", Description: "Whether questions must have all or any of the tags", Schema: &openapi.Schema{
+				Type: "string", Enum: []any{service.TagMatchAll, service.TagMatchAny},
+			}},
+		},
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Page of questions", doc.Schema(model.QuestionPage{})),
+			service.ErrInvalidLimit, service.ErrInvalidCursor, service.ErrInvalidTag, service.ErrInvalidTagMatch),
+	})
+	doc.Add("GET /tags", &openapi.Operation{
+		OperationID: "listTags", Summary: "List tags with the number of questions labelled with them", Tags: []string{"questions"},
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Tags", doc.Schema([]model.TagCount{}))),
+	})
+
+	doc.Add("POST /questions/{id}/answers/", &openapi.Operation{
+		OperationID: "createAnswer", Summary: "Answer a question", Tags: []string{"answers"}, Security: authenticated,
+		Parameters:  []*openapi.Parameter{questionIDParam, idempotencyKeyParam},
+		RequestBody: jsonBody(textBody),
+		Responses: problemResponses(withLocation(withETag(jsonResponse(http.StatusCreated, "Created answer", answer))),
+			errInvalidQuestionID, errMalformedBody, errInvalidBody, service.ErrEmptyText, service.ErrInvalidUserID, service.ErrInvalidIdempotencyKey,
+			service.ErrUnauthenticated, service.ErrQuestionNotExists, errBodyTooLarge, service.ErrIdempotencyKeyInProgress, service.ErrIdempotencyKeyReused),
+	})
+	doc.Add("DELETE /answers/{id}", &openapi.Operation{
+		OperationID: "deleteAnswer", Summary: "Delete an answer", Tags: []string{"answers"}, Security: authenticated,
+		Parameters: []*openapi.Parameter{answerIDParam, ifMatchParam},
+		Responses: problemResponses(noContent("Answer deleted"),
+			errInvalidAnswerID, errMultipleEntityTags, service.ErrInvalidUserID, service.ErrUnauthenticated, service.ErrForbidden,
+			service.ErrAnswerNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("POST /answers/{id}/restore", &openapi.Operation{
+		OperationID: "restoreAnswer", Summary: "Restore a deleted answer", Tags: []string{"answers"}, Security: authenticated,
+		Parameters: []*openapi.Parameter{answerIDParam, ifMatchParam},
+		Responses: problemResponses(withETag(jsonResponse(http.StatusOK, "Restored answer", answer)),
+			errInvalidAnswerID, errMultipleEntityTags, service.ErrInvalidUserID, service.ErrUnauthenticated, service.ErrForbidden,
+			service.ErrAnswerNotExists, service.ErrQuestionNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("GET /answers/{id}", &openapi.Operation{
+		OperationID: "getAnswer", Summary: "Get an answer", Tags: []string{"answers"},
+		Parameters: []*openapi.Parameter{answerIDParam, ifNoneMatchParam},
+		Responses: problemResponses(withETag(jsonResponse(http.StatusOK, "Answer", answer)),
+			errInvalidAnswerID, service.ErrAnswerNotExists),
+	})
+	addNotModified(doc.Operation("GET /answers/{id}"))
+	doc.Add("PATCH /answers/{id}", &openapi.Operation{
+		OperationID: "updateAnswer", Summary: "Edit the text of an answer", Tags: []string{"answers"}, Security: authenticated,
+		Parameters:  []*openapi.Parameter{answerIDParam, ifMatchParam},
+		RequestBody: jsonBody(textBody),
+		Responses: problemResponses(withETag(jsonResponse(http.StatusOK, "Updated answer", answer)),
+			errInvalidAnswerID, errMalformedBody, errInvalidBody, errBodyTooLarge, errMultipleEntityTags, service.ErrEmptyText, service.ErrInvalidUserID,
+			service.ErrUnauthenticated, service.ErrForbidden, service.ErrAnswerNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("GET /answers/{id}/revisions", &openapi.Operation{
+		OperationID: "getAnswerRevisions", Summary: "List prior versions of the text of an answer", Tags: []string{"answers"},
+		Parameters: []*openapi.Parameter{answerIDParam},
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Revisions of the text", revisions),
+			errInvalidAnswerID, service.ErrAnswerNotExists),
+	})
+	doc.Add("POST /answers/{id}/vote", &openapi.Operation{
+		OperationID: "voteAnswer", Summary: "Vote for an answer", Tags: []string{"answers"}, Security: authenticated,
+		Parameters:  []*openapi.Parameter{answerIDParam, ifMatchParam},
+		RequestBody: jsonBody(voteBody),
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Score of the answer", summary),
+			errInvalidAnswerID, errMalformedBody, errInvalidBody, errBodyTooLarge, errMultipleEntityTags, service.ErrInvalidVote, service.ErrInvalidUserID,
+			service.ErrUnauthenticated, service.ErrAnswerNotExists, service.ErrPreconditionFailed),
+	})
+	doc.Add("DELETE /answers/{id}/vote", &openapi.Operation{
+		OperationID: "unvoteAnswer", Summary: "Withdraw the vote for an answer", Tags: []string{"answers"}, Security: authenticated,
+		Parameters: []*openapi.Parameter{answerIDParam, ifMatchParam},
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Score of the answer", summary),
+			errInvalidAnswerID, errMultipleEntityTags, service.ErrInvalidUserID, service.ErrUnauthenticated,
+			service.ErrAnswerNotExists, service.ErrPreconditionFailed),
+	})
+
+	doc.Add("GET /search", &openapi.Operation{
+		OperationID: "search", Summary: "Search questions and answers by their text", Tags: []string{"search"},
+		Parameters: []*openapi.Parameter{
+			{Name: "q", In: "query", Required: true, Description: "Search query", Schema: &openapi.Schema{Type: "string", MinLength: openapi.Ptr(1)}},
+			limitParam,
+		},
+		Responses: problemResponses(jsonResponse(http.StatusOK, "Hits, best first", doc.Schema(model.SearchResult{})),
+			service.ErrEmptyQuery, service.ErrInvalidLimit),
+	})
+
+	return doc
+}
+
+// object describes a request body object with the given properties, which rejects unknown ones.
+func object(properties map[string]*openapi.Schema, required ...string) *openapi.Schema {
+	return &openapi.Schema{Type: "object", Properties: properties, Required: required, AdditionalProperties: openapi.Ptr(false)}
+}
+
+func jsonBody(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{"application/json": {Schema: schema}}}
+}
+
+// statusResponse pairs a response with its status code.
+type statusResponse struct {
+	status   int
+	response *openapi.Response
+}
+
+func jsonResponse(status int, description string, schema *openapi.Schema) statusResponse {
+	return statusResponse{status, &openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{"application/json": {Schema: schema}},
+	}}
+}
+
+func noContent(description string) statusResponse {
+	return statusResponse{http.StatusNoContent, &openapi.Response{Description: description}}
+}
+
+func withETag(sr statusResponse) statusResponse {
+	sr.response.Headers = map[string]*openapi.Header{
+		"ETag": {Description: "Strong entity tag of the current version", Schema: &openapi.Schema{Type: "string"}},
+	}
+	return sr
+}
+
+// withLocation adds the Location header to the response creating a resource.
+func withLocation(sr statusResponse) statusResponse {
+	sr.response.Headers["Location"] = &openapi.Header{Description: "Path of the created resource", Schema: &openapi.Schema{Type: "string"}}
+	return sr
+}
+
+// addNotModified adds the response to a GET request whose If-None-Match header matches the current version.
+func addNotModified(op *openapi.Operation) {
+	op.Responses[strconv.Itoa(http.StatusNotModified)] = &openapi.Response{Description: "The client has the current version"}
+}
+
+// commonErrors may be responded with by any operation: AuthMiddleware rejects invalid tokens on every route,
+// and RateLimitMiddleware can be configured to limit any route.
+var commonErrors = []error{auth.ErrInvalidToken, service.ErrRateLimitExceeded}
+
+// problemResponses builds the responses of an operation from its successful response and the errors it may respond with.
+// Errors sharing a status code are described by a single response listing their problem types.
+// An internal server error may happen anywhere, so it is added too.
+func problemResponses(success statusResponse, errs ...error) map[string]*openapi.Response {
+	responses := map[string]*openapi.Response{strconv.Itoa(success.status): success.response}
+
+	byStatus := make(map[int][]problemType)
+	for _, err := range append(errs, commonErrors...) {
+		pt := lookupProblemType(err)
+		if pt == internalProblem {
+			panic(fmt.Sprintf("error %q has no problem type", err))
+		}
+		byStatus[pt.status] = append(byStatus[pt.status], pt)
+	}
+	byStatus[internalProblem.status] = []problemType{internalProblem}
+
+	for status, types := range byStatus {
+		descriptions := make([]string, len(types))
+		for i, pt := range types {
+			descriptions[i] = fmt.Sprintf("%s (`%s%s`)", pt.title, problemTypeBase, pt.slug)
+		}
+		response := &openapi.Response{
+			Description: strings.Join(descriptions, ", "),
+			Content:     map[string]openapi.MediaType{"application/problem+json": {Schema: openapi.Ref("Problem")}},
+		}
+		if slices.Contains(types, lookupProblemType(service.ErrRateLimitExceeded)) {
+			response.Headers = map[string]*openapi.Header{
+				"Retry-After": {Description: "Seconds until the next request is admitted", Schema: &openapi.Schema{Type: "integer"}},
+			}
+		}
+		responses[strconv.Itoa(status)] = response
+	}
+	return responses
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ppb03/qna-api/internal/mocks"
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registeredRoutes returns the patterns NewRouter registers, read from its source
+// since http.ServeMux cannot list them.
+func registeredRoutes(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "handler.go", nil, 0)
+	require.NoError(t, err)
+
+	var patterns []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if fn, ok := n.(*ast.FuncDecl); ok {
+			return fn.Name.Name == "NewRouter"
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "HandleFunc" {
+			pattern, err := strconv.Unquote(call.Args[0].(*ast.BasicLit).Value)
+			require.NoError(t, err)
+			patterns = append(patterns, pattern)
+		}
+		return true
+	})
+	require.NotEmpty(t, patterns)
+	return patterns
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+func TestOpenAPI_DescribesRegisteredRoutes(t *testing.T) {
+	spec := apiSpec()
+	router := NewRouter(nil, nil, nil)
+
+	registered := registeredRoutes(t)
+	for _, pattern := range registered {
+		assert.NotNil(t, spec.Operation(pattern), "route %q is missing from the OpenAPI document", pattern)
+	}
+
+	var described int
+	for path, item := range spec.Paths {
+		for method := range item {
+			described++
+			req := httptest.NewRequest(strings.ToUpper(method), pathParam.ReplaceAllString(path, "1"), nil)
+			_, pattern := router.Handler(req)
+			assert.Equal(t, strings.ToUpper(method)+" "+path, pattern, "the document describes a route which is not registered")
+		}
+	}
+	assert.Equal(t, len(registered), described)
+}
+
+func TestOpenAPI_Served(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterOpenAPI(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &document))
+	assert.Equal(t, "3.1.0", document["openapi"])
+	assert.Contains(t, document["paths"], "/questions/{id}/answers/")
+}
+
+// documentedResponse records a response served by a route and checks it against the OpenAPI document.
+type documentedResponse struct {
+	t      *testing.T
+	router *http.ServeMux
+	served map[string]bool
+}
+
+func (d *documentedResponse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rr := httptest.NewRecorder()
+	d.router.ServeHTTP(rr, r)
+
+	pattern := routePattern(d.router, r)
+	d.served[pattern] = true
+	op := apiSpec().Operation(pattern)
+	require.NotNil(d.t, op, pattern)
+
+	response, ok := op.Responses[strconv.Itoa(rr.Code)]
+	if !assert.True(d.t, ok, "status %d of %s %s is missing from the OpenAPI document: %s", rr.Code, r.Method, r.URL, rr.Body) {
+		return
+	}
+	if len(response.Content) == 0 {
+		assert.Empty(d.t, rr.Body.String(), "%s %s", r.Method, r.URL)
+		return
+	}
+
+	mediaType, ok := response.Content[rr.Header().Get("Content-Type")]
+	require.True(d.t, ok, "content type of %s %s", r.Method, r.URL)
+	var body any
+	require.NoError(d.t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.NoError(d.t, apiSpec().Validate(mediaType.Schema, body), "body of %s %s: %s", r.Method, r.URL, rr.Body)
+}
+
+func TestOpenAPI_DocumentsResponses(t *testing.T) {
+	router, _ := newMemoryRouter(mocks.NewUserRepository(model.User{ID: testModeratorID, Role: model.RoleModerator}))
+	checker := &documentedResponse{t: t, router: router, served: make(map[string]bool)}
+
+	requests := []struct {
+		method, path, body, userID string
+		header                     http.Header
+	}{
+		{"POST", "/questions/", `{"text": "Test question", "tags": ["go"]}`, testUserID, nil},
+		{"POST", "/questions/", `{"text": ""}`, testUserID, nil},
+		{"POST", "/questions/", `{"text": "Test question"}`, "", nil},
+		{"GET", "/questions/", "", "", nil},
+		{"GET", "/questions/?limit=0", "", "", nil},
+		{"GET", "/tags", "", "", nil},
+		{"GET", "/questions/1", "", "", nil},
+		{"GET", "/questions/1", "", "", http.Header{"If-None-Match": {`"1"`}}},
+		{"GET", "/questions/abc", "", "", nil},
+		{"GET", "/questions/99", "", "", nil},
+		{"PATCH", "/questions/1", `{"text": "Edited question"}`, testUserID, nil},
+		{"PATCH", "/questions/1", `{"text": "Lost update"}`, testUserID, http.Header{"If-Match": {`"1"`}}},
+		{"PATCH", "/questions/1", `{"text": "Someone else's edit"}`, testOtherUserID, nil},
+		{"GET", "/questions/1/revisions", "", "", nil},
+		{"POST", "/questions/1/vote", `{"value": 1}`, testOtherUserID, nil},
+		{"POST", "/questions/1/vote", `{"value": 2}`, testOtherUserID, nil},
+		{"DELETE", "/questions/1/vote", "", testOtherUserID, nil},
+		{"POST", "/questions/1/answers/", `{"text": "Test answer"}`, testOtherUserID, nil},
+		{"POST", "/questions/99/answers/", `{"text": "Test answer"}`, testOtherUserID, nil},
+		{"GET", "/answers/1", "", "", nil},
+		{"GET", "/answers/1", "", "", http.Header{"If-None-Match": {`"1"`}}},
+		{"GET", "/answers/99", "", "", nil},
+		{"PATCH", "/answers/1", `{"text": "Edited answer"}`, testOtherUserID, nil},
+		{"GET", "/answers/1/revisions", "", "", nil},
+		{"POST", "/answers/1/vote", `{"value": -1}`, testUserID, nil},
+		{"DELETE", "/answers/1/vote", "", testUserID, nil},
+		{"POST", "/questions/1/accept/1", "", testUserID, nil},
+		{"POST", "/questions/1/accept/1", "", testUserID, http.Header{"If-Match": {`"1", "2"`}}},
+		{"GET", "/search?q=edited", "", "", nil},
+		{"GET", "/search", "", "", nil},
+		{"DELETE", "/answers/1", "", testOtherUserID, nil},
+		{"POST", "/answers/1/restore", "", testOtherUserID, nil},
+		{"POST", "/answers/1/restore", "", testModeratorID, nil},
+		{"DELETE", "/questions/1", "", testOtherUserID, nil},
+		{"DELETE", "/questions/1", "", testUserID, nil},
+		{"POST", "/questions/1/restore", "", testModeratorID, nil},
+	}
+
+	for _, r := range requests {
+		req := httptest.NewRequest(r.method, r.path, strings.NewReader(r.body))
+		if r.userID != "" {
+			req = withUser(req, r.userID)
+		}
+		for name, values := range r.header {
+			req.Header[name] = values
+		}
+		checker.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	for _, pattern := range registeredRoutes(t) {
+		assert.True(t, checker.served[pattern], "no request to %q is checked against the OpenAPI document", pattern)
+	}
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	router, _ := newMemoryRouter(repository.NewMemoryUserRepository())
+	handler := ValidationMiddleware(router)(router)
+
+	cases := map[string]struct {
+		body    string
+		status  int
+		problem string
+		detail  string
+	}{
+		"valid":            {`{"text": "Test question", "tags": ["go"]}`, http.StatusCreated, "", ""},
+		"missing text":     {`{"tags": ["go"]}`, http.StatusBadRequest, "invalid-body", `property "text" is required`},
+		"empty text":       {`{"text": ""}`, http.StatusBadRequest, "invalid-body", "/text: must be at least 1 characters long"},
+		"tag not a string": {`{"text": "Test question", "tags": [1]}`, http.StatusBadRequest, "invalid-body", "/tags/0: must be a string"},
+		"unknown property": {`{"text": "Test question", "title": "Test"}`, http.StatusBadRequest, "invalid-body", `property "title" is not allowed`},
+		"not JSON":         {`{"text":`, http.StatusBadRequest, "malformed-body", ""},
+		"too large":        {`{"text": "` + strings.Repeat("a", maxBodySize) + `"}`, http.StatusRequestEntityTooLarge, "body-too-large", "the limit is"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest("POST", "/questions/", strings.NewReader(c.body)), testUserID)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, c.status, rr.Code, rr.Body.String())
+			if c.problem == "" {
+				return
+			}
+			var problem Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+			assert.Equal(t, problemTypeBase+c.problem, problem.Type)
+			assert.Contains(t, problem.Detail, c.detail)
+		})
+	}
+}
+
+func TestValidationMiddleware_IgnoresRoutesWithoutBody(t *testing.T) {
+	router, _ := newMemoryRouter(repository.NewMemoryUserRepository())
+	handler := ValidationMiddleware(router)(router)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/questions/", strings.NewReader("not JSON")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
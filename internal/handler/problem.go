@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/logging"
+	"github.com/ppb03/qna-api/internal/service"
+)
+
+// Errors detected by handlers before the request reaches the service layer.
+var (
+	errInvalidQuestionID = errors.New("invalid question ID")
+	errInvalidAnswerID   = errors.New("invalid answer ID")
+	errMalformedBody     = errors.New("malformed request body")
+	errInvalidBody       = errors.New("request body does not match the schema")
+	errBodyTooLarge      = errors.New("request body is too large")
+)
+
+// problemTypeBase prefixes the slug of every problem type to form its stable type URI.
+const problemTypeBase = "urn:qna-api:problem:"
+
+// Problem is an RFC 7807 problem details object returned with the application/problem+json content type.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	RequestID string `json:"request_id"`
+}
+
+// problemType describes how an error is presented to clients.
+type problemType struct {
+	slug   string
+	title  string
+	status int
+}
+
+// problemTypes lists errors along with their problem types. An error matching several of them, e.g. one joined
+// with errors.Join, gets the type listed first. Errors matching none of them are treated as internal.
+var problemTypes = []struct {
+	err error
+	problemType
+}{
+	{errInvalidQuestionID, problemType{"invalid-question-id", "Invalid question ID", http.StatusBadRequest}},
+	{errInvalidAnswerID, problemType{"invalid-answer-id", "Invalid answer ID", http.StatusBadRequest}},
+	{errMalformedBody, problemType{"malformed-body", "Malformed request body", http.StatusBadRequest}},
+	{errInvalidBody, problemType{"invalid-body", "Invalid request body", http.StatusBadRequest}},
+	{service.ErrEmptyText, problemType{"empty-text", "Empty text", http.StatusBadRequest}},
+	{service.ErrInvalidUserID, problemType{"invalid-user-id", "Invalid user ID", http.StatusBadRequest}},
+	{service.ErrInvalidLimit, problemType{"invalid-limit", "Invalid limit", http.StatusBadRequest}},
+	{service.ErrInvalidCursor, problemType{"invalid-cursor", "Invalid cursor", http.StatusBadRequest}},
+	{service.ErrEmptyQuery, problemType{"empty-query", "Empty search query", http.StatusBadRequest}},
+	{service.ErrInvalidVote, problemType{"invalid-vote", "Invalid vote", http.StatusBadRequest}},
+	{service.ErrAnswerNotInQuestion, problemType{"answer-not-in-question", "Answer does not belong to the question", http.StatusBadRequest}},
+	{service.ErrInvalidTag, problemType{"invalid-tag", "Invalid tag", http.StatusBadRequest}},
+	{service.ErrInvalidTagMatch, problemType{"invalid-tag-match", "Invalid tag match", http.StatusBadRequest}},
+	{errMultipleEntityTags, problemType{"multiple-entity-tags", "Multiple entity tags", http.StatusBadRequest}},
+	{errBodyTooLarge, problemType{"body-too-large", "Request body too large", http.StatusRequestEntityTooLarge}},
+
+	{service.ErrInvalidIdempotencyKey, problemType{"invalid-idempotency-key", "Invalid idempotency key", http.StatusBadRequest}},
+	{service.ErrIdempotencyKeyInProgress, problemType{"idempotency-key-in-progress", "Request with the same idempotency key is in progress", http.StatusConflict}},
+	{service.ErrIdempotencyKeyReused, problemType{"idempotency-key-reused", "Idempotency key reused with a different request", http.StatusUnprocessableEntity}},
+
+	{service.ErrUnauthenticated, problemType{"unauthenticated", "Authentication required", http.StatusUnauthorized}},
+	{auth.ErrInvalidToken, problemType{"invalid-token", "Invalid token", http.StatusUnauthorized}},
+	{service.ErrForbidden, problemType{"forbidden", "Forbidden", http.StatusForbidden}},
+	{service.ErrNotQuestionAuthor, problemType{"not-question-author", "Not the author of the question", http.StatusForbidden}},
+
+	{service.ErrQuestionNotExists, problemType{"question-not-found", "Question not found", http.StatusNotFound}},
+	{service.ErrAnswerNotExists, problemType{"answer-not-found", "Answer not found", http.StatusNotFound}},
+
+	{service.ErrPreconditionFailed, problemType{"precondition-failed", "Precondition failed", http.StatusPreconditionFailed}},
+	{service.ErrRateLimitExceeded, problemType{"rate-limit-exceeded", "Rate limit exceeded", http.StatusTooManyRequests}},
+}
+
+// internalProblem is used for errors not found in problemTypes. Their details are logged only.
+var internalProblem = problemType{"internal", "Internal server error", http.StatusInternalServerError}
+
+// malformedBody wraps a request body decoding error.
+func malformedBody(err error) error {
+	return fmt.Errorf("%w: %v", errMalformedBody, err)
+}
+
+// maxBodySize limits the request bodies which middleware reads into memory before they reach the handlers.
+const maxBodySize = 1 << 20
+
+// readBody reads the body of r, failing with errBodyTooLarge if it exceeds maxBodySize,
+// and replaces it with a copy for the next handler to read.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, fmt.Errorf("%w: the limit is %d bytes", errBodyTooLarge, maxBodySize)
+		}
+		return nil, malformedBody(err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// writeProblem responds with the problem details describing err.
+// Internal errors are logged along with the request ID and replaced with a generic detail.
+func writeProblem(w http.ResponseWriter, r *http.Request, err error) {
+	pt := lookupProblemType(err)
+	problem := Problem{
+		Type:      problemTypeBase + pt.slug,
+		Title:     pt.title,
+		Status:    pt.status,
+		Detail:    err.Error(),
+		RequestID: requestID(r),
+	}
+
+	if pt == internalProblem {
+		logger := logging.FromContext(r.Context())
+		if _, ok := logging.RequestIDFromContext(r.Context()); !ok {
+			logger = logger.With("request_id", problem.RequestID)
+		}
+		logger.Error("request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		problem.Detail = "the server failed to process the request"
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+func lookupProblemType(err error) problemType {
+	for _, entry := range problemTypes {
+		if errors.Is(err, entry.err) {
+			return entry.problemType
+		}
+	}
+	return internalProblem
+}
+
+// requestID returns the ID assigned to the request by RequestIDMiddleware. Without the middleware
+// it falls back to the X-Request-ID header or a new random ID if the header is missing.
+func requestID(r *http.Request) string {
+	if id, ok := logging.RequestIDFromContext(r.Context()); ok {
+		return id
+	}
+	if id := r.Header.Get(requestIDHeader); id != "" {
+		return id
+	}
+	return newRequestID()
+}
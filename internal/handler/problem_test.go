@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ppb03/qna-api/internal/mocks"
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProblem_ClientError(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(999)).
+		Return((*model.Question)(nil), repository.ErrQuestionNotFound)
+
+	req := httptest.NewRequest("GET", "/questions/999", nil)
+	req.Header.Set("X-Request-ID", "test-request-id")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+
+	var problem Problem
+	err := json.Unmarshal(rr.Body.Bytes(), &problem)
+	assert.NoError(t, err)
+	assert.Equal(t, Problem{
+		Type:      "urn:qna-api:problem:question-not-found",
+		Title:     "Question not found",
+		Status:    http.StatusNotFound,
+		Detail:    service.ErrQuestionNotExists.Error(),
+		RequestID: "test-request-id",
+	}, problem)
+}
+
+func TestProblem_InternalErrorIsNotExposed(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	mockQuestionRepo.On("GetByID", mock.Anything, uint(1)).
+		Return((*model.Question)(nil), errors.New(`pq: relation "questions" does not exist`))
+
+	req := httptest.NewRequest("GET", "/questions/1", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "relation")
+	assert.NotContains(t, rr.Body.String(), service.ErrRepositoryFailure.Error())
+
+	var problem Problem
+	err := json.Unmarshal(rr.Body.Bytes(), &problem)
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:qna-api:problem:internal", problem.Type)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.NotEmpty(t, problem.RequestID)
+}
+
+func TestProblem_MalformedBody(t *testing.T) {
+	mockQuestionRepo := new(mocks.MockQuestionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	questionService := service.NewQuestionService(mockQuestionRepo, mockUserRepo)
+	handler := NewRouter(questionService, nil, nil)
+
+	req := withUser(httptest.NewRequest("POST", "/questions/", strings.NewReader("{")), testUserID)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var problem Problem
+	err := json.Unmarshal(rr.Body.Bytes(), &problem)
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:qna-api:problem:malformed-body", problem.Type)
+	assert.True(t, strings.HasPrefix(problem.Detail, errMalformedBody.Error()))
+}
+
+func TestProblem_ErrorMatchingSeveralTypes(t *testing.T) {
+	err := errors.Join(service.ErrQuestionNotExists, fmt.Errorf("%w, retry in 1 s", service.ErrRateLimitExceeded))
+
+	for range 20 {
+		rr := httptest.NewRecorder()
+		writeProblem(rr, httptest.NewRequest("GET", "/questions/1", nil), err)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code, "the type listed first wins")
+	}
+}
@@ -11,21 +11,24 @@ import (
 func createQuestion(svc service.QuestionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		rbody := struct {
-			Text string `json:"text"`
+			Text string   `json:"text"`
+			Tags []string `json:"tags"`
 		}{}
 		
 		if err := json.NewDecoder(r.Body).Decode(&rbody); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeProblem(w, r, malformedBody(err))
 			return
 		}
 		
-		question, err := svc.Create(r.Context(), rbody.Text)
+		question, err := svc.Create(r.Context(), rbody.Text, rbody.Tags)
 		if err != nil {
-			http.Error(w, err.Error(), errorStatusCode(err))
+			writeProblem(w, r, err)
 			return
 		}
 		
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", entityTag(question.Version))
+		w.Header().Set("Location", "/questions/"+strconv.FormatUint(uint64(question.ID), 10))
 		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(question)
 	}
@@ -35,12 +38,18 @@ func deleteQuestion(svc service.QuestionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
 		if err != nil {
-			http.Error(w, "invalid question ID", http.StatusBadRequest)
+			writeProblem(w, r, errInvalidQuestionID)
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
 			return
 		}
 
-		if err := svc.Delete(r.Context(), uint(id)); err != nil {
-			http.Error(w, err.Error(), errorStatusCode(err))
+		if err := svc.Delete(r.Context(), uint(id), version); err != nil {
+			writeProblem(w, r, err)
 			return
 		}
 
@@ -48,35 +57,240 @@ func deleteQuestion(svc service.QuestionService) http.HandlerFunc {
 	}
 }
 
+func restoreQuestion(svc service.QuestionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidQuestionID)
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		question, err := svc.Restore(r.Context(), uint(id), version)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", entityTag(question.Version))
+		json.NewEncoder(w).Encode(question)
+	}
+}
+
 func getQuestionByID(svc service.QuestionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
 		if err != nil {
-			http.Error(w, "invalid question ID", http.StatusBadRequest)
+			writeProblem(w, r, errInvalidQuestionID)
 			return
 		}
 
 		
 		question, err := svc.GetByID(r.Context(), uint(id))
 		if err != nil {
-			http.Error(w, err.Error(), errorStatusCode(err))
+			writeProblem(w, r, err)
+			return
+		}
+
+		etag := entityTag(question.Version)
+		w.Header().Set("ETag", etag)
+		if notModified(r, etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(question)
+	}
+}
+
+func updateQuestion(svc service.QuestionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidQuestionID)
+			return
+		}
+
+		rbody := struct {
+			Text string `json:"text"`
+		}{}
+
+		if err := json.NewDecoder(r.Body).Decode(&rbody); err != nil {
+			writeProblem(w, r, malformedBody(err))
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		question, err := svc.Update(r.Context(), uint(id), rbody.Text, version)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", entityTag(question.Version))
+		json.NewEncoder(w).Encode(question)
+	}
+}
+
+func getQuestionRevisions(svc service.QuestionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidQuestionID)
+			return
+		}
+
+		revisions, err := svc.GetRevisions(r.Context(), uint(id))
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(revisions)
+	}
+}
+
+func voteQuestion(svc service.QuestionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidQuestionID)
+			return
+		}
+
+		rbody := struct {
+			Value int `json:"value"`
+		}{}
+
+		if err := json.NewDecoder(r.Body).Decode(&rbody); err != nil {
+			writeProblem(w, r, malformedBody(err))
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		summary, err := svc.Vote(r.Context(), uint(id), rbody.Value, version)
+		if err != nil {
+			writeProblem(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summary)
+	}
+}
+
+func unvoteQuestion(svc service.QuestionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidQuestionID)
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		summary, err := svc.Unvote(r.Context(), uint(id), version)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summary)
+	}
+}
+
+func acceptAnswer(svc service.QuestionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidQuestionID)
+			return
+		}
+
+		answerID, err := strconv.ParseUint(r.PathValue("answerID"), 10, 32)
+		if err != nil {
+			writeProblem(w, r, errInvalidAnswerID)
+			return
+		}
+
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		question, err := svc.Accept(r.Context(), uint(id), uint(answerID), version)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", entityTag(question.Version))
 		json.NewEncoder(w).Encode(question)
 	}
 }
 
 func getAllQuestions(svc service.QuestionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		questions, err := svc.GetAll(r.Context())
+		params := service.QuestionListParams{
+			Cursor: r.URL.Query().Get("cursor"),
+			Tags:   r.URL.Query()["tag"],
+			Match:  r.URL.Query().Get("match"),
+		}
+		if limit := r.URL.Query().Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n < 1 {
+				writeProblem(w, r, service.ErrInvalidLimit)
+				return
+			}
+			params.Limit = n
+		}
+
+		page, err := svc.GetAll(r.Context(), params)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}
+}
+
+func getTags(svc service.QuestionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tags, err := svc.GetTags(r.Context())
 		if err != nil {
-			http.Error(w, err.Error(), errorStatusCode(err))
+			writeProblem(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(questions)
+		json.NewEncoder(w).Encode(tags)
 	}
 }
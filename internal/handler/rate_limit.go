@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/service"
+)
+
+// RateLimitMiddleware limits the rate of requests each client makes to the routes in limits, which maps the route patterns
+// registered by NewRouter to their limits. Clients are identified by the authenticated user, so it has to run after
+// AuthMiddleware, and anonymous ones by the IP address. Every response of a limited route carries the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers, and rejected requests get 429 along with the Retry-After header.
+// Requests are let through when the token buckets cannot be reached, so that an outage of their store does not take the API down.
+func RateLimitMiddleware(router *http.ServeMux, svc service.RateLimitService, limits map[string]model.RateLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pattern := routePattern(router, r)
+			limit, ok := limits[pattern]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Failures are logged by the service.
+			status, err := svc.Allow(r.Context(), pattern+" "+rateLimitClient(r), limit)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(status.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(status.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(status.Reset)))
+			if !status.Allowed {
+				retryAfter := max(ceilSeconds(status.RetryAfter), 1)
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				writeProblem(w, r, fmt.Errorf("%w, retry in %d s", service.ErrRateLimitExceeded, retryAfter))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitClient identifies the client making a request by the authenticated user or, for anonymous requests, by the IP address.
+func rateLimitClient(r *http.Request) string {
+	if userID, ok := auth.SubjectFromContext(r.Context()); ok {
+		return "user:" + userID
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ceilSeconds rounds d up to whole seconds, as the rate limit headers require.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedHandler() http.Handler {
+	router, _ := newMemoryRouter(repository.NewMemoryUserRepository())
+	limits := map[string]model.RateLimit{
+		"POST /questions/": {Requests: 1, Period: time.Hour, Burst: 2},
+		"GET /tags":        {Requests: 1, Period: time.Hour, Burst: 1},
+	}
+	rateLimitService := service.NewRateLimitService(repository.NewMemoryRateLimitRepository())
+	return RateLimitMiddleware(router, rateLimitService, limits)(router)
+}
+
+func createQuestionAs(h http.Handler, userID string) *httptest.ResponseRecorder {
+	req := withUser(httptest.NewRequest("POST", "/questions/", strings.NewReader(`{"text": "Test question"}`)), userID)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimit_RejectsExhaustedBucket(t *testing.T) {
+	h := newRateLimitedHandler()
+
+	for remaining := 1; remaining >= 0; remaining-- {
+		rr := createQuestionAs(h, testUserID)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(remaining), rr.Header().Get("RateLimit-Remaining"))
+		assert.Empty(t, rr.Header().Get("Retry-After"))
+	}
+
+	rr := createQuestionAs(h, testUserID)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "urn:qna-api:problem:rate-limit-exceeded", problemTypeOf(t, rr))
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "3600", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "7200", rr.Header().Get("RateLimit-Reset"), "both tokens are refilled in two hours")
+}
+
+func TestRateLimit_KeysByUser(t *testing.T) {
+	h := newRateLimitedHandler()
+
+	for range 2 {
+		require.Equal(t, http.StatusCreated, createQuestionAs(h, testUserID).Code)
+	}
+	require.Equal(t, http.StatusTooManyRequests, createQuestionAs(h, testUserID).Code)
+
+	assert.Equal(t, http.StatusCreated, createQuestionAs(h, testOtherUserID).Code)
+}
+
+func TestRateLimit_KeysAnonymousByIP(t *testing.T) {
+	h := newRateLimitedHandler()
+
+	getTags := func(remoteAddr string) int {
+		req := httptest.NewRequest("GET", "/tags", nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, getTags("192.0.2.1:1234"))
+	assert.Equal(t, http.StatusTooManyRequests, getTags("192.0.2.1:5678"), "the port does not identify the client")
+	assert.Equal(t, http.StatusOK, getTags("192.0.2.2:1234"))
+}
+
+func TestRateLimit_IgnoresUnlistedRoutes(t *testing.T) {
+	h := newRateLimitedHandler()
+
+	for range 3 {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("GET", "/questions/", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+	}
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ppb03/qna-api/internal/service"
+)
+
+func search(svc service.SearchService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var limit int
+		if l := r.URL.Query().Get("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil || n < 1 {
+				writeProblem(w, r, service.ErrInvalidLimit)
+				return
+			}
+			limit = n
+		}
+
+		result, err := svc.Search(r.Context(), r.URL.Query().Get("q"), limit)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
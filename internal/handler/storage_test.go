@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/internal/repository/repositorytest"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRouter creates a router backed by the given repositories.
+func newRouter(questionRepository repository.QuestionRepository, answerRepository repository.AnswerRepository,
+	searchRepository repository.SearchRepository, userRepository repository.UserRepository) *http.ServeMux {
+	return NewRouter(
+		service.NewQuestionService(questionRepository, userRepository),
+		service.NewAnswerService(answerRepository, questionRepository, userRepository),
+		service.NewSearchService(searchRepository),
+	)
+}
+
+// memoryBackend is the in-memory storage behind a router created by newMemoryRouter, for tests to seed and inspect.
+type memoryBackend struct {
+	store     *repository.MemoryStore
+	questions repository.QuestionRepository
+	answers   repository.AnswerRepository
+}
+
+// newMemoryRouter creates a router backed by in-memory storage which looks users up in users,
+// and returns it along with the storage.
+func newMemoryRouter(users repository.UserRepository) (*http.ServeMux, memoryBackend) {
+	store := repository.NewMemoryStore()
+	backend := memoryBackend{
+		store:     store,
+		questions: repository.NewMemoryQuestionRepository(store),
+		answers:   repository.NewMemoryAnswerRepository(store),
+	}
+	return newRouter(backend.questions, backend.answers, repository.NewMemorySearchRepository(store), users), backend
+}
+
+// storageBackends creates routers backed by each storage that works without external services.
+var storageBackends = map[string]func(t *testing.T) http.Handler{
+	"memory": func(t *testing.T) http.Handler {
+		store := repository.NewMemoryStore()
+		return newRouter(
+			repository.NewMemoryQuestionRepository(store),
+			repository.NewMemoryAnswerRepository(store),
+			repository.NewMemorySearchRepository(store),
+			repository.NewMemoryUserRepository(),
+		)
+	},
+	"sqlite": func(t *testing.T) http.Handler {
+		db := repositorytest.OpenSQLite(t)
+		return newRouter(
+			repository.NewSQLiteQuestionRepository(db),
+			repository.NewSQLiteAnswerRepository(db),
+			repository.NewSQLiteSearchRepository(db),
+			repository.NewSQLiteUserRepository(db),
+		)
+	},
+}
+
+// serveJSON sends a request authenticated as testUserID and decodes the JSON response into dst, if any.
+func serveJSON(t *testing.T, handler http.Handler, method, target string, body any, dst any) int {
+	t.Helper()
+
+	var reader bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reader).Encode(body))
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, withUser(httptest.NewRequest(method, target, &reader), testUserID))
+	if dst != nil && rr.Code < 300 {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), dst))
+	}
+	return rr.Code
+}
+
+func TestStorage_QuestionLifecycle(t *testing.T) {
+	for name, newBackend := range storageBackends {
+		t.Run(name, func(t *testing.T) {
+			testQuestionLifecycle(t, newBackend(t))
+		})
+	}
+}
+
+func testQuestionLifecycle(t *testing.T, handler http.Handler) {
+
+	var question model.Question
+	code := serveJSON(t, handler, "POST", "/questions/", map[string]any{"text": "How to sort a map in Go?", "tags": []string{"Go"}}, &question)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, uint(1), question.ID)
+	assert.False(t, question.CreatedAt.IsZero())
+
+	var first, second model.Answer
+	answersURL := fmt.Sprintf("/questions/%d/answers/", question.ID)
+	require.Equal(t, http.StatusCreated, serveJSON(t, handler, "POST", answersURL, map[string]string{"text": "Sort the keys"}, &first))
+	require.Equal(t, http.StatusCreated, serveJSON(t, handler, "POST", answersURL, map[string]string{"text": "Use slices.Sorted"}, &second))
+	var script model.Answer
+	require.Equal(t, http.StatusCreated, serveJSON(t, handler, "POST", answersURL, map[string]string{"text": "<script>alert('xss')</script> & more"}, &script))
+	assert.Equal(t, first.ID+1, second.ID)
+	assert.Equal(t, second.ID+1, script.ID)
+
+	var summary model.VoteSummary
+	require.Equal(t, http.StatusOK, serveJSON(t, handler, "POST", fmt.Sprintf("/answers/%d/vote", second.ID), map[string]int{"value": 1}, &summary))
+	assert.Equal(t, 1, summary.Score)
+
+	var fetched model.Question
+	require.Equal(t, http.StatusOK, serveJSON(t, handler, "GET", fmt.Sprintf("/questions/%d", question.ID), nil, &fetched))
+	if assert.Len(t, fetched.Answers, 3) {
+		assert.Equal(t, second.ID, fetched.Answers[0].ID)
+	}
+	assert.Equal(t, []model.Tag{{Name: "go"}}, fetched.Tags)
+
+	var result model.SearchResult
+	require.Equal(t, http.StatusOK, serveJSON(t, handler, "GET", "/search?q=KEYS", nil, &result))
+	if assert.Len(t, result.Hits, 1) {
+		assert.Equal(t, first.ID, result.Hits[0].ID)
+		assert.Equal(t, "Sort the <mark>keys</mark>", result.Hits[0].Snippet)
+	}
+	require.Equal(t, http.StatusOK, serveJSON(t, handler, "GET", "/search?q=more", nil, &result))
+	if assert.Len(t, result.Hits, 1) {
+		assert.Equal(t, "&lt;script&gt;alert(&#39;xss&#39;)&lt;/script&gt; &amp; <mark>more</mark>", result.Hits[0].Snippet, "user text is escaped")
+	}
+
+	assert.Equal(t, http.StatusNoContent, serveJSON(t, handler, "DELETE", fmt.Sprintf("/questions/%d", question.ID), nil, nil))
+	assert.Equal(t, http.StatusNotFound, serveJSON(t, handler, "GET", fmt.Sprintf("/questions/%d", question.ID), nil, nil))
+	assert.Equal(t, http.StatusNotFound, serveJSON(t, handler, "GET", fmt.Sprintf("/answers/%d", first.ID), nil, nil))
+}
+
+func TestStorage_Pagination(t *testing.T) {
+	for name, newBackend := range storageBackends {
+		t.Run(name, func(t *testing.T) {
+			handler := newBackend(t)
+
+			for i := range 3 {
+				body := map[string]any{"text": fmt.Sprintf("Question %d", i+1), "tags": []string{"go"}}
+				require.Equal(t, http.StatusCreated, serveJSON(t, handler, "POST", "/questions/", body, nil))
+			}
+
+			var page model.QuestionPage
+			require.Equal(t, http.StatusOK, serveJSON(t, handler, "GET", "/questions/?limit=2&tag=go", nil, &page))
+			require.Len(t, page.Questions, 2)
+			require.NotEmpty(t, page.NextCursor)
+			assert.Equal(t, "Question 1", page.Questions[0].Text)
+
+			var next model.QuestionPage
+			require.Equal(t, http.StatusOK, serveJSON(t, handler, "GET", "/questions/?limit=2&cursor="+page.NextCursor, nil, &next))
+			if assert.Len(t, next.Questions, 1) {
+				assert.Equal(t, "Question 3", next.Questions[0].Text)
+			}
+			assert.Empty(t, next.NextCursor)
+
+			var tags []model.TagCount
+			require.Equal(t, http.StatusOK, serveJSON(t, handler, "GET", "/tags", nil, &tags))
+			assert.Equal(t, []model.TagCount{{Name: "go", Count: 3}}, tags)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a server span for each request named after the pattern of the route in router
+// which serves it, e.g. "GET /questions/{id}". The trace is continued from the W3C traceparent header if present.
+func TracingMiddleware(router *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withRoute := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			trace.SpanFromContext(r.Context()).SetAttributes(semconv.HTTPRoute(routePattern(router, r)))
+			next.ServeHTTP(w, r)
+		})
+		return otelhttp.NewHandler(withRoute, "http.server", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return routePattern(router, r)
+		}))
+	}
+}
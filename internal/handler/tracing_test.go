@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/internal/repository/repositorytest"
+	"github.com/ppb03/qna-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanRecorder records the spans of every test. Tracers obtained before a provider is set globally keep delegating
+// to the first one set, so the provider is set once for the whole package.
+var spanRecorder = sync.OnceValue(func() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+})
+
+// recordSpans returns the recorder of the spans ended during the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := spanRecorder()
+	recorder.Reset()
+
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func TestTracing_SpansAcrossLayers(t *testing.T) {
+	recorder := recordSpans(t)
+
+	db := repositorytest.OpenSQLite(t)
+	require.NoError(t, db.Use(repository.NewTracingPlugin()))
+	questionRepository := repository.NewSQLiteQuestionRepository(db)
+	userRepository := repository.NewSQLiteUserRepository(db)
+	router := NewRouter(
+		service.NewTracedQuestionService(service.NewQuestionService(questionRepository, userRepository)),
+		nil,
+		nil,
+	)
+	handler := TracingMiddleware(router)(router)
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest("GET", "/questions/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, traceID, span.SpanContext().TraceID().String(), span.Name())
+		spans[span.Name()] = span
+	}
+
+	server, ok := spans["GET /questions/{id}"]
+	require.True(t, ok, "server span is missing: %v", spans)
+	assert.Equal(t, parentSpanID, server.Parent().SpanID().String())
+
+	serviceSpan, ok := spans["QuestionService.GetByID"]
+	require.True(t, ok, "service span is missing: %v", spans)
+	assert.Equal(t, server.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+
+	query, ok := spans["SELECT questions"]
+	require.True(t, ok, "query span is missing: %v", spans)
+	assert.Equal(t, serviceSpan.SpanContext().SpanID(), query.Parent().SpanID())
+}
+
+func TestTracing_WrapsEveryServiceMethod(t *testing.T) {
+	recorder := recordSpans(t)
+
+	// The wrapped services are nil interfaces embedded in structs, so each call panics once the wrapper delegates it.
+	// The span of the wrapper still ends while the panic unwinds.
+	services := []struct {
+		iface  reflect.Type
+		traced any
+	}{
+		{reflect.TypeFor[service.QuestionService](), service.NewTracedQuestionService(struct{ service.QuestionService }{})},
+		{reflect.TypeFor[service.AnswerService](), service.NewTracedAnswerService(struct{ service.AnswerService }{})},
+		{reflect.TypeFor[service.SearchService](), service.NewTracedSearchService(struct{ service.SearchService }{})},
+		{reflect.TypeFor[service.PurgeService](), service.NewTracedPurgeService(struct{ service.PurgeService }{})},
+		{reflect.TypeFor[service.IdempotencyService](), service.NewTracedIdempotencyService(struct{ service.IdempotencyService }{})},
+		{reflect.TypeFor[service.RateLimitService](), service.NewTracedRateLimitService(struct{ service.RateLimitService }{})},
+	}
+
+	for _, s := range services {
+		for i := range s.iface.NumMethod() {
+			method := s.iface.Method(i)
+			name := s.iface.Name() + "." + method.Name
+			t.Run(name, func(t *testing.T) {
+				call := reflect.ValueOf(s.traced).MethodByName(method.Name)
+				args := []reflect.Value{reflect.ValueOf(context.Background())}
+				for i := 1; i < call.Type().NumIn(); i++ {
+					args = append(args, reflect.Zero(call.Type().In(i)))
+				}
+
+				recorder.Reset()
+				assert.Panics(t, func() { call.Call(args) })
+
+				var names []string
+				for _, span := range recorder.Ended() {
+					names = append(names, span.Name())
+				}
+				assert.Equal(t, []string{name}, names, "the traced wrapper must start a span named after the method")
+			})
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package metrics defines the Prometheus metrics of the application.
+//
+// Collectors are registered in the default Prometheus registry, which also carries the Go runtime and process metrics.
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "qna"
+
+// UnmatchedRoute labels requests which matched none of the routes.
+const UnmatchedRoute = "unmatched"
+
+var (
+	// HTTPRequests counts handled requests by route pattern and status code.
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by route pattern and status code.",
+	}, []string{"route", "status"})
+
+	// HTTPErrors counts requests which failed with a server error (status 5xx) by route pattern.
+	HTTPErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_request_errors_total",
+		Help:      "Number of HTTP requests failed with a server error by route pattern.",
+	}, []string{"route"})
+
+	// HTTPDuration observes the time spent serving requests by route pattern.
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time spent serving HTTP requests by route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route"})
+
+	// QuestionsCreated counts questions created since the start.
+	QuestionsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "questions_created_total",
+		Help:      "Number of questions created.",
+	})
+
+	// AnswersCreated counts answers created since the start.
+	AnswersCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "answers_created_total",
+		Help:      "Number of answers created.",
+	})
+)
+
+// RegisterDB exposes the connection pool statistics of db reported by sql.DB.Stats().
+func RegisterDB(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
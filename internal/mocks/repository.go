@@ -5,9 +5,11 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/ppb03/qna-api/internal/model"
-	
+	"github.com/ppb03/qna-api/internal/repository"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -23,11 +25,21 @@ func (m *MockQuestionRepository) Create(ctx context.Context, question *model.Que
 	return args.Get(0).(*model.Question), args.Error(1)
 }
 
-func (m *MockQuestionRepository) Delete(ctx context.Context, id uint) error {
-	args := m.Called(ctx, id)
+func (m *MockQuestionRepository) Delete(ctx context.Context, id uint, version int) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockQuestionRepository) Restore(ctx context.Context, id uint, version int) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
+func (m *MockQuestionRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockQuestionRepository) GetByID(ctx context.Context, id uint) (*model.Question, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -36,8 +48,44 @@ func (m *MockQuestionRepository) GetByID(ctx context.Context, id uint) (*model.Q
 	return args.Get(0).(*model.Question), args.Error(1)
 }
 
-func (m *MockQuestionRepository) GetAll(ctx context.Context) ([]model.Question, error) {
+func (m *MockQuestionRepository) Vote(ctx context.Context, id uint, userID string, value, version int) (int, error) {
+	args := m.Called(ctx, id, userID, value, version)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQuestionRepository) Unvote(ctx context.Context, id uint, userID string, version int) (int, error) {
+	args := m.Called(ctx, id, userID, version)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQuestionRepository) GetTags(ctx context.Context) ([]model.TagCount, error) {
 	args := m.Called(ctx)
+	return args.Get(0).([]model.TagCount), args.Error(1)
+}
+
+func (m *MockQuestionRepository) Accept(ctx context.Context, id, answerID uint, version int) error {
+	args := m.Called(ctx, id, answerID, version)
+	return args.Error(0)
+}
+
+func (m *MockQuestionRepository) Update(ctx context.Context, id uint, text, editorID string, version int) (*model.Question, error) {
+	args := m.Called(ctx, id, text, editorID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepository) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Revision), args.Error(1)
+}
+
+func (m *MockQuestionRepository) GetAll(ctx context.Context, query repository.QuestionQuery) ([]model.Question, error) {
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -56,15 +104,85 @@ func (m *MockAnswerRepository) Create(ctx context.Context, answer *model.Answer)
 	return args.Get(0).(*model.Answer), args.Error(1)
 }
 
-func (m *MockAnswerRepository) Delete(ctx context.Context, id uint) error {
-	args := m.Called(ctx, id)
+func (m *MockAnswerRepository) Delete(ctx context.Context, id uint, version int) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockAnswerRepository) Restore(ctx context.Context, id uint, version int) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
+func (m *MockAnswerRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockAnswerRepository) GetByID(ctx context.Context, id uint) (*model.Answer, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Answer), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *MockAnswerRepository) Vote(ctx context.Context, id uint, userID string, value, version int) (int, error) {
+	args := m.Called(ctx, id, userID, value, version)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAnswerRepository) Unvote(ctx context.Context, id uint, userID string, version int) (int, error) {
+	args := m.Called(ctx, id, userID, version)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAnswerRepository) Update(ctx context.Context, id uint, text, editorID string, version int) (*model.Answer, error) {
+	args := m.Called(ctx, id, text, editorID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Answer), args.Error(1)
+}
+
+func (m *MockAnswerRepository) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Revision), args.Error(1)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+// NewUserRepository creates a MockUserRepository which finds the given users only and reports the others as not found.
+func NewUserRepository(users ...model.User) *MockUserRepository {
+	m := new(MockUserRepository)
+	for _, user := range users {
+		m.On("GetByID", mock.Anything, user.ID).Return(&user, nil)
+	}
+	m.On("GetByID", mock.Anything, mock.Anything).Return(nil, repository.ErrUserNotFound)
+	return m
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*model.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, query string, limit int) ([]model.SearchHit, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SearchHit), args.Error(1)
+}
@@ -4,25 +4,225 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 
 // Question represents a question entity in the system.
-// It contains the question text, creation timestamp, and associated answers.
+// It contains the author's identity, question text, creation timestamp, and associated answers.
+// AcceptedAnswerID points to the answer the author marked as the solution, if any.
+// Tags categorize the question and are serialized as a list of tag names.
+// Version is incremented whenever the question or any of its answers changes and serves as its entity tag.
+// Deleted questions are kept with DeletedAt set until they are purged.
 type Question struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Text      string    `json:"text" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	Answers   []Answer  `json:"answers,omitempty" gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	AuthorID         string         `json:"author_id,omitempty" gorm:"size:255;index"`
+	Text             string         `json:"text" gorm:"not null"`
+	Score            int            `json:"score" gorm:"not null;default:0"`
+	AcceptedAnswerID *uint          `json:"accepted_answer_id,omitempty"`
+	Version          int            `json:"version" gorm:"not null;default:1"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+	Answers          []Answer       `json:"answers,omitempty" gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+	Tags             []Tag          `json:"tags,omitempty" gorm:"many2many:question_tags;constraint:OnDelete:CASCADE"`
 }
 
 // Answer represents an answer to a question in the system.
 // It links to a specific question and includes the responder's identity and answer content.
+// Version is incremented whenever the answer changes and serves as its entity tag.
+// Deleted answers are kept with DeletedAt set until they are purged.
 type Answer struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	QuestionID uint           `json:"question_id" gorm:"index;not null"`
+	UserID     string         `json:"user_id" gorm:"size:255;not null;index"`
+	Text       string         `json:"text" gorm:"not null"`
+	Score      int            `json:"score" gorm:"not null;default:0"`
+	Version    int            `json:"version" gorm:"not null;default:1"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Tag represents a normalized label used to categorize questions.
+// It is serialized to JSON as its bare name.
+type Tag struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"size:32;uniqueIndex;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// MarshalJSON encodes a tag as its name.
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name)
+}
+
+// UnmarshalJSON decodes a tag from its name.
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &t.Name)
+}
+
+// TagCount represents a tag along with the number of questions labelled with it.
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// Vote represents an up (+1) or down (-1) vote cast by a user for a question or an answer.
+// Exactly one of QuestionID and AnswerID is set, a user has at most one vote per question or answer.
+type Vote struct {
 	ID         uint      `json:"id" gorm:"primaryKey"`
-	QuestionID uint      `json:"question_id" gorm:"index;not null"`
-	UserID     string    `json:"user_id" gorm:"not null;index"`
+	UserID     string    `json:"user_id" gorm:"size:255;not null"`
+	QuestionID *uint     `json:"question_id,omitempty" gorm:"index"`
+	AnswerID   *uint     `json:"answer_id,omitempty" gorm:"index"`
+	Value      int       `json:"value" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// VoteSummary represents the score of a question or an answer after the caller cast or withdrew a vote.
+// Vote is the caller's current vote: 1, -1, or 0 when the caller has no vote.
+type VoteSummary struct {
+	Score int `json:"score"`
+	Vote  int `json:"vote"`
+}
+
+// Roles a User may have.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// User represents a registered user and their role in the system.
+// Users without a record are treated as having RoleUser.
+type User struct {
+	ID        string    `json:"id" gorm:"size:255;primaryKey"`
+	Role      string    `json:"role" gorm:"size:32;not null;default:user"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// IdempotencyRecord represents a request made with an Idempotency-Key header and the response to replay when it is retried.
+// Keys are scoped to the user who made the request. StatusCode is zero while the request is being processed.
+// Headers holds the response headers to replay along with the body. The record is discarded once ExpiresAt passes,
+// so the key can be used again.
+type IdempotencyRecord struct {
+	UserID      string            `gorm:"size:255;primaryKey"`
+	Key         string            `gorm:"column:idempotency_key;size:255;primaryKey"`
+	RequestHash string            `gorm:"size:64;not null"`
+	StatusCode  int               `gorm:"not null;default:0"`
+	Headers     map[string]string `gorm:"serializer:json;type:text"`
+	Body        []byte
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+// RateLimit allows a client Burst requests at once, replenished at the rate of Requests per Period.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// Interval returns the time it takes to replenish one request.
+func (l RateLimit) Interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// RateLimitBucket represents the token bucket of a client limited by a RateLimit, one token per request.
+// Tokens are replenished continuously since RefilledAt. Once ExpiresAt passes the bucket is full
+// and can be discarded, since a missing bucket is treated as a full one.
+type RateLimitBucket struct {
+	Key        string    `gorm:"column:bucket_key;size:255;primaryKey"`
+	Tokens     float64   `gorm:"not null"`
+	RefilledAt time.Time `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null;index"`
+}
+
+// RateLimitStatus describes the bucket of a client right after a request was admitted or rejected.
+// Remaining is the number of requests the client can make at once, Reset is the time until the bucket is full,
+// and RetryAfter is the time until the next request is admitted, which is zero unless the request was rejected.
+type RateLimitStatus struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Revision represents a prior version of a question or answer text.
+// It is recorded each time the text is edited and keeps the replaced text along with
+// the identity of the editor who replaced it and the time of the edit.
+// Exactly one of QuestionID and AnswerID is set.
+type Revision struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	QuestionID *uint     `json:"question_id,omitempty" gorm:"index"`
+	AnswerID   *uint     `json:"answer_id,omitempty" gorm:"index"`
 	Text       string    `json:"text" gorm:"not null"`
+	EditorID   string    `json:"editor_id" gorm:"size:255;not null"`
 	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	Diff       []DiffOp  `json:"diff,omitempty" gorm:"-"`
+}
+
+// Kinds of DiffOp operations.
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffOp represents a single word-level edit operation between two versions of a text.
+type DiffOp struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// QuestionPage represents a single page of questions returned by a paginated listing.
+// NextCursor is empty when there are no more questions to fetch.
+type QuestionPage struct {
+	Questions  []Question `json:"questions"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// Statuses of a HealthReport and its checks.
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// HealthReport represents the result of a readiness probe.
+// Status is HealthOK only when every check passed.
+type HealthReport struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck represents the result of a single readiness check of a dependency.
+// Error explains why the check failed.
+type HealthCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// Kinds of entities a SearchHit may refer to.
+const (
+	SearchHitQuestion = "question"
+	SearchHitAnswer   = "answer"
+)
+
+// SearchHit represents a single full-text search match in either a question or an answer.
+// Snippet contains the matched fragment of the text, HTML-escaped, with matched terms wrapped in <mark> tags.
+type SearchHit struct {
+	Type       string  `json:"type"`
+	ID         uint    `json:"id"`
+	QuestionID uint    `json:"question_id"`
+	Rank       float64 `json:"rank"`
+	Snippet    string  `json:"snippet"`
+}
+
+// SearchResult represents ranked full-text search hits across questions and answers.
+type SearchResult struct {
+	Hits []SearchHit `json:"hits"`
 }
@@ -0,0 +1,164 @@
+// Package openapi describes HTTP APIs with OpenAPI 3.1 documents and validates JSON values against their schemas.
+//
+// Only the parts of the specification the API makes use of are modelled.
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification documents conform to.
+const Version = "3.1.0"
+
+// Document is the root object of an OpenAPI document.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lowercase HTTP methods to the operations available on a path.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+// Parameter describes a single path, query or header parameter of an operation.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of a request by its media types.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response of an operation by its headers and media types.
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]*Header   `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header describes a response header.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType holds the schema of a request or response body of a media type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas and security schemes referred to from the rest of the document.
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes an authentication scheme of the API.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirement maps the names of security schemes to the scopes they require.
+// An empty requirement makes authentication optional.
+type SecurityRequirement map[string][]string
+
+// Schema is a JSON Schema as used by OpenAPI 3.1, limited to the keywords Validate understands.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// Ptr returns a pointer to v, for the optional keywords of Schema.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// New creates an empty document of the API with the given title and version.
+func New(title, version, description string) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: title, Version: version, Description: description},
+		Paths:      make(map[string]PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+}
+
+// Add describes the operation served by the route with the given pattern, like "GET /questions/{id}".
+// The pattern uses the syntax of http.ServeMux, which path templates of OpenAPI share.
+func (d *Document) Add(pattern string, op *Operation) {
+	method, path := splitPattern(pattern)
+	if d.Paths[path] == nil {
+		d.Paths[path] = make(PathItem)
+	}
+	d.Paths[path][method] = op
+}
+
+// Operation returns the operation served by the route with the given pattern, or nil if the document lacks it.
+func (d *Document) Operation(pattern string) *Operation {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		return nil
+	}
+	return d.Paths[path][strings.ToLower(method)]
+}
+
+func splitPattern(pattern string) (method, path string) {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		panic(fmt.Sprintf("openapi: route pattern %q has no method", pattern))
+	}
+	return strings.ToLower(method), path
+}
+
+// Resolve follows the reference of schema to the components of d. Schemas without a reference are returned as is.
+func (d *Document) Resolve(schema *Schema) *Schema {
+	if schema.Ref == "" {
+		return schema
+	}
+	resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]
+	if !ok {
+		panic(fmt.Sprintf("openapi: unresolved schema reference %q", schema.Ref))
+	}
+	return resolved
+}
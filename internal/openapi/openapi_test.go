@@ -0,0 +1,113 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type label struct{ name string }
+
+func (l label) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.name)
+}
+
+type base struct {
+	ID uint `json:"id"`
+}
+
+type post struct {
+	base
+	Text      string    `json:"text"`
+	Score     int       `json:"score,omitempty"`
+	Parent    *post     `json:"parent,omitempty"`
+	Labels    []label   `json:"labels"`
+	CreatedAt time.Time `json:"created_at"`
+	Internal  string    `json:"-"`
+	hidden    string
+}
+
+func TestSchema(t *testing.T) {
+	doc := New("Test API", "1.0.0", "")
+	doc.Define(label{}, &Schema{Type: "string"})
+
+	assert.Equal(t, &Schema{Type: "array", Items: Ref("post")}, doc.Schema([]post{}))
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":         {Type: "integer", Minimum: Ptr(0.0)},
+			"text":       {Type: "string"},
+			"score":      {Type: "integer"},
+			"parent":     Ref("post"),
+			"labels":     {Type: "array", Items: Ref("label")},
+			"created_at": {Type: "string", Format: "date-time"},
+		},
+		Required: []string{"id", "text", "labels", "created_at"},
+	}, doc.Components.Schemas["post"])
+}
+
+func TestSchema_CustomEncodingMustBeDefined(t *testing.T) {
+	doc := New("Test API", "1.0.0", "")
+
+	assert.Panics(t, func() { doc.Schema(label{}) })
+}
+
+func TestValidate(t *testing.T) {
+	doc := New("Test API", "1.0.0", "")
+	doc.Define(label{}, &Schema{Type: "string", MinLength: Ptr(1)})
+	doc.Schema(post{})
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"post": Ref("post"),
+			"vote": {Type: "integer", Enum: []any{1, -1}},
+			"tags": {Type: "array", MaxItems: Ptr(2), Items: &Schema{Type: "string"}},
+		},
+		AdditionalProperties: Ptr(false),
+	}
+
+	cases := map[string]struct {
+		value string
+		err   string
+	}{
+		"valid":                {`{"post": {"id": 1, "text": "a", "labels": ["b"], "created_at": "2026-01-02T03:04:05Z"}, "vote": -1}`, ""},
+		"not an object":        {`[]`, "must be an object"},
+		"unknown property":     {`{"title": "a"}`, `property "title" is not allowed`},
+		"missing required":     {`{"post": {"id": 1, "labels": [], "created_at": "2026-01-02T03:04:05Z"}}`, `/post: property "text" is required`},
+		"negative unsigned":    {`{"post": {"id": -1, "text": "a", "labels": [], "created_at": "2026-01-02T03:04:05Z"}}`, "/post/id: must be at least 0"},
+		"fraction":             {`{"post": {"id": 1.5, "text": "a", "labels": [], "created_at": "2026-01-02T03:04:05Z"}}`, "/post/id: must be an integer"},
+		"malformed date":       {`{"post": {"id": 1, "text": "a", "labels": [], "created_at": "yesterday"}}`, "/post/created_at: must be a date-time"},
+		"short defined string": {`{"post": {"id": 1, "text": "a", "labels": [""], "created_at": "2026-01-02T03:04:05Z"}}`, "/post/labels/0: must be at least 1 characters long"},
+		"not in enum":          {`{"vote": 0}`, "/vote: must be one of 1, -1"},
+		"too many items":       {`{"tags": ["a", "b", "c"]}`, "/tags: must have at most 2 items"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var value any
+			require.NoError(t, json.Unmarshal([]byte(c.value), &value))
+
+			err := doc.Validate(schema, value)
+			if c.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, c.err, err.Error())
+		})
+	}
+}
+
+func TestOperation(t *testing.T) {
+	doc := New("Test API", "1.0.0", "")
+	op := &Operation{OperationID: "getPost"}
+	doc.Add("GET /posts/{id}", op)
+
+	assert.Same(t, op, doc.Operation("GET /posts/{id}"))
+	assert.Nil(t, doc.Operation("DELETE /posts/{id}"))
+	assert.Nil(t, doc.Operation(""), "requests matching no route have no operation")
+}
@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// schemaRefPrefix prefixes the names of component schemas in references.
+const schemaRefPrefix = "#/components/schemas/"
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	marshalerType = reflect.TypeFor[json.Marshaler]()
+)
+
+// Ref returns a schema referring to the component schema with the given name.
+func Ref(name string) *Schema {
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// Define adds schema to the components of d under the type name of v. It is meant for types with custom JSON encoding,
+// which Schema cannot derive, and has to be called before Schema meets such a type.
+func (d *Document) Define(v any, schema *Schema) *Schema {
+	name := reflect.TypeOf(v).Name()
+	d.Components.Schemas[name] = schema
+	return Ref(name)
+}
+
+// Schema derives the schema of v as encoded by encoding/json. Named structs are added to the components of d under
+// their type names and referred to, so each of them is described once. Fields tagged with omitempty are optional,
+// all others are required since they are always encoded.
+func (d *Document) Schema(v any) *Schema {
+	return d.schemaOf(reflect.TypeOf(v))
+}
+
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return d.schemaOf(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		if _, ok := d.Components.Schemas[t.Name()]; !ok {
+			if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+				panic(fmt.Sprintf("openapi: %s has custom JSON encoding, its schema has to be defined", t))
+			}
+			// The name is taken before the fields are described, so that recursive types refer to themselves.
+			d.Components.Schemas[t.Name()] = nil
+			d.Components.Schemas[t.Name()] = d.structSchema(t)
+		}
+		return Ref(t.Name())
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schemaOf(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: Ptr(0.0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	panic(fmt.Sprintf("openapi: cannot derive the schema of %s", t))
+}
+
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	d.addFields(schema, t)
+	return schema
+}
+
+// addFields describes the exported fields of struct t as properties of schema, flattening embedded structs.
+func (d *Document) addFields(schema *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			d.addFields(schema, field.Type)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = d.schemaOf(field.Type)
+		if !strings.Contains(","+options+",", ",omitempty,") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
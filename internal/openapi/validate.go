@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError describes the first part of a value which does not match its schema.
+// Path is the JSON Pointer of the part, empty for the value itself.
+type ValidationError struct {
+	Path   string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Reason
+	}
+	return e.Path + ": " + e.Reason
+}
+
+// Validate checks value, as decoded from JSON by encoding/json into an interface, against schema.
+// References are resolved against the components of d. It returns a *ValidationError if value does not match.
+func (d *Document) Validate(schema *Schema, value any) error {
+	return d.validate(schema, value, "")
+}
+
+func (d *Document) validate(schema *Schema, value any, path string) error {
+	schema = d.Resolve(schema)
+	invalid := func(format string, args ...any) error {
+		return &ValidationError{Path: path, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(allowed any) bool { return sameJSON(allowed, value) }) {
+		return invalid("must be one of %s", enumList(schema.Enum))
+	}
+
+	switch schema.Type {
+	case "":
+		return nil
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return invalid("must be an object")
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return invalid("property %q is required", name)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(object)) {
+			property, ok := schema.Properties[name]
+			if !ok {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					return invalid("property %q is not allowed", name)
+				}
+				continue
+			}
+			if err := d.validate(property, object[name], path+"/"+escapePointer(name)); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return invalid("must be an array")
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			return invalid("must have at most %d items", *schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for i, item := range array {
+				if err := d.validate(schema.Items, item, path+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return invalid("must be a string")
+		}
+		length := utf8.RuneCountInString(s)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			return invalid("must be at least %d characters long", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			return invalid("must be at most %d characters long", *schema.MaxLength)
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return invalid("must be a date-time")
+			}
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return invalid("must be a number")
+		}
+		if schema.Type == "integer" && n != math.Trunc(n) {
+			return invalid("must be an integer")
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			return invalid("must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			return invalid("must be at most %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalid("must be a boolean")
+		}
+	default:
+		panic(fmt.Sprintf("openapi: unsupported schema type %q", schema.Type))
+	}
+	return nil
+}
+
+// sameJSON reports whether a and b have the same JSON encoding, so that an enum value of int matches a decoded float64.
+func sameJSON(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+func enumList(values []any) string {
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		b, _ := json.Marshal(value)
+		encoded[i] = string(b)
+	}
+	return strings.Join(encoded, ", ")
+}
+
+// escapePointer escapes a property name to be a JSON Pointer reference token.
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/internal/repository/repositorytest"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// postgresDSNEnv names the environment variable with the DSN of a PostgreSQL database to run the tests against.
+// Every test gets its own schema in that database, so it may be shared with other data.
+const postgresDSNEnv = "QNA_TEST_POSTGRES_DSN"
+
+// postgresDSN returns the DSN set in postgresDSNEnv, skipping the test if it is not set.
+func postgresDSN(t *testing.T) string {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skip(postgresDSNEnv + " is not set")
+	}
+	return dsn
+}
+
+func TestMemoryRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Backend {
+		store := repository.NewMemoryStore()
+		return repositorytest.Backend{
+			Questions:   repository.NewMemoryQuestionRepository(store),
+			Answers:     repository.NewMemoryAnswerRepository(store),
+			Users:       repository.NewMemoryUserRepository(repositorytest.Users()...),
+			Search:      repository.NewMemorySearchRepository(store),
+			Idempotency: repository.NewMemoryIdempotencyRepository(store),
+			RateLimits:  repository.NewMemoryRateLimitRepository(),
+		}
+	})
+}
+
+func TestSQLiteRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Backend {
+		db := repositorytest.OpenSQLite(t)
+		seedUsers(t, db)
+		return repositorytest.Backend{
+			Questions:   repository.NewSQLiteQuestionRepository(db),
+			Answers:     repository.NewSQLiteAnswerRepository(db),
+			Users:       repository.NewSQLiteUserRepository(db),
+			Search:      repository.NewSQLiteSearchRepository(db),
+			Idempotency: repository.NewSQLiteIdempotencyRepository(db),
+			RateLimits:  repository.NewSQLiteRateLimitRepository(db),
+		}
+	})
+}
+
+func TestPostgresRepositoryContract(t *testing.T) {
+	dsn := postgresDSN(t)
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Backend {
+		db := repositorytest.OpenPostgres(t, dsn)
+		seedUsers(t, db)
+
+		return repositorytest.Backend{
+			Questions:   repository.NewPostgresQuestionRepository(db),
+			Answers:     repository.NewPostgresAnswerRepository(db),
+			Users:       repository.NewPostgresUserRepository(db),
+			Search:      repository.NewPostgresSearchRepository(db),
+			Idempotency: repository.NewPostgresIdempotencyRepository(db),
+			RateLimits:  repository.NewPostgresRateLimitRepository(db),
+		}
+	})
+}
+
+// seedUsers stores the users every contract Backend is expected to have.
+func seedUsers(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	users := repositorytest.Users()
+	require.NoError(t, db.Create(&users).Error)
+}
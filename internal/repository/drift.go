@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Kinds of SchemaDrift.
+const (
+	DriftMissingTable        = "missing_table"
+	DriftMissingColumn       = "missing_column"
+	DriftTypeMismatch        = "type_mismatch"
+	DriftNullabilityMismatch = "nullability_mismatch"
+	DriftMissingIndex        = "missing_index"
+)
+
+// SchemaDrift represents a single difference between the schema gorm derives from the model structs
+// and the schema of the database created by the migrations.
+type SchemaDrift struct {
+	Kind     string `json:"kind"`
+	Table    string `json:"table"`
+	Column   string `json:"column,omitempty"`
+	Index    string `json:"index,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// String formats the drift as a single line suitable for logs.
+func (d SchemaDrift) String() string {
+	target := d.Table
+	switch {
+	case d.Column != "":
+		target += "." + d.Column
+	case d.Index != "":
+		target += " index " + d.Index
+	}
+	line := d.Kind + " " + target
+	if d.Expected != "" {
+		line += ": expected " + d.Expected
+	}
+	if d.Actual != "" {
+		line += ", actual " + d.Actual
+	}
+	return line
+}
+
+// persistedModels lists the structs stored by the gorm repositories.
+var persistedModels = []any{
+	&model.Question{},
+	&model.Answer{},
+	&model.Revision{},
+	&model.User{},
+	&model.Vote{},
+	&model.Tag{},
+	&model.IdempotencyRecord{},
+	&model.RateLimitBucket{},
+}
+
+// dbColumn describes a column as reported by the database.
+type dbColumn struct {
+	Name     string
+	Type     string
+	Length   int64
+	Nullable bool
+}
+
+// dbIndex describes an index as reported by the database, including the ones backing constraints.
+type dbIndex struct {
+	Columns []string
+	Unique  bool
+}
+
+// CheckSchema compares the schema gorm derives from the model structs with the schema of db,
+// which may be PostgreSQL or SQLite, and returns the differences found. Types are compared by kind
+// (text, integer, timestamp...) and by length for strings. Columns and indexes which exist
+// only in the database, such as the full-text search vectors, are not reported.
+func CheckSchema(ctx context.Context, db *gorm.DB) ([]SchemaDrift, error) {
+	inspector, err := newSchemaInspector(db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := persistedSchemas(db)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := []SchemaDrift{}
+	for _, sch := range schemas {
+		tableDrifts, err := checkTable(inspector, sch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect table %s: %w", sch.Table, err)
+		}
+		drifts = append(drifts, tableDrifts...)
+	}
+	return drifts, nil
+}
+
+// persistedSchemas parses persistedModels along with the join tables of their many-to-many relations.
+func persistedSchemas(db *gorm.DB) ([]*schema.Schema, error) {
+	var (
+		schemas []*schema.Schema
+		cache   sync.Map
+		seen    = map[string]bool{}
+	)
+	add := func(sch *schema.Schema) {
+		if !seen[sch.Table] {
+			seen[sch.Table] = true
+			schemas = append(schemas, sch)
+		}
+	}
+
+	for _, m := range persistedModels {
+		sch, err := schema.Parse(m, &cache, db.NamingStrategy)
+		if err != nil {
+			return nil, err
+		}
+		add(sch)
+		for _, relation := range sch.Relationships.Many2Many {
+			if relation.JoinTable != nil {
+				add(relation.JoinTable)
+			}
+		}
+	}
+	return schemas, nil
+}
+
+func checkTable(inspector schemaInspector, sch *schema.Schema) ([]SchemaDrift, error) {
+	columns, err := inspector.columns(sch.Table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return []SchemaDrift{{Kind: DriftMissingTable, Table: sch.Table}}, nil
+	}
+
+	var drifts []SchemaDrift
+	for _, field := range sch.Fields {
+		if field.DBName == "" || field.DataType == "" || field.IgnoreMigration {
+			continue
+		}
+
+		column, ok := columns[field.DBName]
+		if !ok {
+			drifts = append(drifts, SchemaDrift{Kind: DriftMissingColumn, Table: sch.Table, Column: field.DBName})
+			continue
+		}
+
+		expectedKind, expectedLength := fieldType(field)
+		if columnKind(column.Type) != expectedKind || (expectedKind == schema.String && column.Length != expectedLength) {
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftTypeMismatch, Table: sch.Table, Column: field.DBName,
+				Expected: describeType(expectedKind, expectedLength), Actual: describeColumn(column),
+			})
+		}
+
+		if nullable := !field.NotNull && !field.PrimaryKey; column.Nullable != nullable {
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftNullabilityMismatch, Table: sch.Table, Column: field.DBName,
+				Expected: describeNullability(nullable), Actual: describeNullability(column.Nullable),
+			})
+		}
+	}
+
+	indexes, err := inspector.indexes(sch.Table)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range sch.ParseIndexes() {
+		columns := make([]string, len(index.Fields))
+		for i, option := range index.Fields {
+			columns[i] = option.DBName
+		}
+		unique := index.Class == "UNIQUE"
+
+		found := slices.ContainsFunc(indexes, func(existing dbIndex) bool {
+			return slices.Equal(existing.Columns, columns) && (existing.Unique || !unique)
+		})
+		if !found {
+			expected := "(" + strings.Join(columns, ", ") + ")"
+			if unique {
+				expected = "unique " + expected
+			}
+			drifts = append(drifts, SchemaDrift{Kind: DriftMissingIndex, Table: sch.Table, Index: index.Name, Expected: expected})
+		}
+	}
+
+	return drifts, nil
+}
+
+// fieldType returns the kind of column a field is stored in and, for strings, its maximum length or zero if unbounded.
+func fieldType(field *schema.Field) (schema.DataType, int64) {
+	switch field.DataType {
+	case schema.Int, schema.Uint:
+		return schema.Int, 0
+	case schema.String:
+		return schema.String, int64(field.Size)
+	default:
+		// Types set explicitly with the type tag, like text for serialized fields, are column types.
+		return columnKind(string(field.DataType)), 0
+	}
+}
+
+// columnKind maps a database column type of PostgreSQL or SQLite to the kind of gorm fields stored in it.
+func columnKind(columnType string) schema.DataType {
+	switch strings.ToLower(columnType) {
+	case "smallint", "integer", "bigint", "int", "int2", "int4", "int8":
+		return schema.Int
+	case "text", "character varying", "varchar", "character", "char":
+		return schema.String
+	case "timestamp with time zone", "timestamp without time zone", "timestamp", "datetime":
+		return schema.Time
+	case "boolean", "bool":
+		return schema.Bool
+	case "real", "double precision", "numeric", "float", "double":
+		return schema.Float
+	case "bytea", "blob":
+		return schema.Bytes
+	default:
+		return schema.DataType(columnType)
+	}
+}
+
+func describeType(kind schema.DataType, length int64) string {
+	switch {
+	case kind == schema.String && length > 0:
+		return "varchar(" + strconv.FormatInt(length, 10) + ")"
+	case kind == schema.String:
+		return "text"
+	case kind == schema.Int:
+		return "integer"
+	case kind == schema.Time:
+		return "timestamp"
+	default:
+		return string(kind)
+	}
+}
+
+func describeColumn(column dbColumn) string {
+	if column.Length > 0 {
+		return column.Type + "(" + strconv.FormatInt(column.Length, 10) + ")"
+	}
+	return column.Type
+}
+
+func describeNullability(nullable bool) string {
+	if nullable {
+		return "null"
+	}
+	return "not null"
+}
+
+// schemaInspector reads the actual schema of a table from the database.
+// Both methods return nothing for a table that does not exist.
+type schemaInspector interface {
+	columns(table string) (map[string]dbColumn, error)
+	indexes(table string) ([]dbIndex, error)
+}
+
+func newSchemaInspector(db *gorm.DB) (schemaInspector, error) {
+	switch name := db.Dialector.Name(); name {
+	case "postgres":
+		return postgresInspector{db: db}, nil
+	case "sqlite":
+		return sqliteInspector{db: db}, nil
+	default:
+		return nil, fmt.Errorf("schema check is not supported for %s", name)
+	}
+}
+
+type postgresInspector struct {
+	db *gorm.DB
+}
+
+func (i postgresInspector) columns(table string) (map[string]dbColumn, error) {
+	var rows []dbColumn
+	err := i.db.Raw(`
+		SELECT column_name AS name, data_type AS type, COALESCE(character_maximum_length, 0) AS length,
+			is_nullable = 'YES' AS nullable
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ?`, table).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return columnsByName(rows), nil
+}
+
+// indexes reads pg_index since information_schema does not describe indexes.
+func (i postgresInspector) indexes(table string) ([]dbIndex, error) {
+	var rows []struct {
+		Unique  bool
+		Columns string
+	}
+	err := i.db.Raw(`
+		SELECT ix.indisunique AS "unique", array_to_string(ARRAY(
+			SELECT a.attname
+			FROM unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+			ORDER BY k.ord
+		), ',') AS columns
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		WHERE t.relname = ? AND t.relnamespace = current_schema()::regnamespace`, table).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]dbIndex, len(rows))
+	for n, row := range rows {
+		indexes[n] = dbIndex{Columns: strings.Split(row.Columns, ","), Unique: row.Unique}
+	}
+	return indexes, nil
+}
+
+type sqliteInspector struct {
+	db *gorm.DB
+}
+
+func (i sqliteInspector) columns(table string) (map[string]dbColumn, error) {
+	var rows []struct {
+		Name    string
+		Type    string
+		NotNull bool
+		PK      int
+	}
+	err := i.db.Raw(`SELECT name, type, "notnull" AS not_null, pk FROM pragma_table_info(?)`, table).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]dbColumn, len(rows))
+	for n, row := range rows {
+		// Declared types keep their length, e.g. VARCHAR(255).
+		columnType, length, _ := strings.Cut(row.Type, "(")
+		size, _ := strconv.ParseInt(strings.TrimSuffix(length, ")"), 10, 64)
+		columns[n] = dbColumn{
+			Name:     row.Name,
+			Type:     strings.ToLower(strings.TrimSpace(columnType)),
+			Length:   size,
+			Nullable: !row.NotNull && row.PK == 0,
+		}
+	}
+	return columnsByName(columns), nil
+}
+
+func (i sqliteInspector) indexes(table string) ([]dbIndex, error) {
+	var list []struct {
+		Name   string
+		Unique bool
+	}
+	if err := i.db.Raw(`SELECT name, "unique" FROM pragma_index_list(?)`, table).Scan(&list).Error; err != nil {
+		return nil, err
+	}
+
+	indexes := make([]dbIndex, len(list))
+	for n, index := range list {
+		indexes[n].Unique = index.Unique
+		err := i.db.Raw(`SELECT name FROM pragma_index_info(?) ORDER BY seqno`, index.Name).Scan(&indexes[n].Columns).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return indexes, nil
+}
+
+func columnsByName(columns []dbColumn) map[string]dbColumn {
+	byName := make(map[string]dbColumn, len(columns))
+	for _, column := range columns {
+		byName[column.Name] = column
+	}
+	return byName
+}
@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ppb03/qna-api/internal/repository"
+	"github.com/ppb03/qna-api/internal/repository/repositorytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// migratedDatabases opens a migrated database of each driver. PostgreSQL is skipped unless postgresDSNEnv is set.
+var migratedDatabases = map[string]func(t *testing.T) *gorm.DB{
+	"sqlite": repositorytest.OpenSQLite,
+	"postgres": func(t *testing.T) *gorm.DB {
+		return repositorytest.OpenPostgres(t, postgresDSN(t))
+	},
+}
+
+func TestCheckSchema_Migrated(t *testing.T) {
+	for name, open := range migratedDatabases {
+		t.Run(name, func(t *testing.T) {
+			drifts, err := repository.CheckSchema(context.Background(), open(t))
+			require.NoError(t, err)
+			assert.Empty(t, drifts)
+		})
+	}
+}
+
+func TestCheckSchema_ReportsDrift(t *testing.T) {
+	for name, open := range migratedDatabases {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			for _, statement := range []string{
+				`DROP INDEX idx_answers_user_id`,
+				`ALTER TABLE revisions DROP COLUMN editor_id`,
+				`DROP TABLE users`,
+				`CREATE TABLE users (id TEXT PRIMARY KEY, role VARCHAR(32), created_at TIMESTAMP)`,
+				`DROP TABLE question_tags`,
+			} {
+				require.NoError(t, db.Exec(statement).Error, statement)
+			}
+
+			drifts, err := repository.CheckSchema(context.Background(), db)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []repository.SchemaDrift{
+				{Kind: repository.DriftMissingIndex, Table: "answers", Index: "idx_answers_user_id", Expected: "(user_id)"},
+				{Kind: repository.DriftMissingColumn, Table: "revisions", Column: "editor_id"},
+				{Kind: repository.DriftTypeMismatch, Table: "users", Column: "id", Expected: "varchar(255)", Actual: "text"},
+				{Kind: repository.DriftNullabilityMismatch, Table: "users", Column: "role", Expected: "not null", Actual: "null"},
+				{Kind: repository.DriftMissingTable, Table: "question_tags"},
+			}, drifts)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormAnswerRepository works with any database supported by gorm whose schema was created by the migrations.
+type gormAnswerRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresAnswerRepository creates AnswerRepository instance which interacts with PostgreSQL database
+func NewPostgresAnswerRepository(db *gorm.DB) AnswerRepository {
+	return &gormAnswerRepository{db: db}
+}
+
+// NewSQLiteAnswerRepository creates AnswerRepository instance which interacts with SQLite database
+func NewSQLiteAnswerRepository(db *gorm.DB) AnswerRepository {
+	return &gormAnswerRepository{db: db}
+}
+
+func (r *gormAnswerRepository) Create(ctx context.Context, answer *model.Answer) (*model.Answer, error) {
+	return answer, r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(answer).Error; err != nil {
+			return err
+		}
+		return touchQuestion(tx, answer.QuestionID)
+	})
+}
+
+func (r *gormAnswerRepository) Delete(ctx context.Context, id uint, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var answer model.Answer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "question_id", "version").First(&answer, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAnswerNotFound
+			}
+			return err
+		}
+		if err := checkVersion(answer.Version, version); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&answer).Error; err != nil {
+			return err
+		}
+		err := tx.Unscoped().Model(&model.Question{}).
+			Where("accepted_answer_id = ?", id).
+			Update("accepted_answer_id", nil).Error
+		if err != nil {
+			return err
+		}
+		return touchQuestion(tx, answer.QuestionID)
+	})
+}
+
+func (r *gormAnswerRepository) Restore(ctx context.Context, id uint, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var answer model.Answer
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&answer, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAnswerNotFound
+			}
+			return err
+		}
+		if err := checkVersion(answer.Version, version); err != nil {
+			return err
+		}
+		if !answer.DeletedAt.Valid {
+			return nil
+		}
+
+		if err := tx.Select("id").First(&model.Question{}, answer.QuestionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrQuestionNotFound
+			}
+			return err
+		}
+		if err := tx.Unscoped().Model(&answer).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return touchQuestion(tx, answer.QuestionID)
+	})
+}
+
+func (r *gormAnswerRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before.UTC()).Delete(&model.Answer{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormAnswerRepository) GetByID(ctx context.Context, id uint) (*model.Answer, error) {
+	var answer model.Answer
+	if err := r.db.WithContext(ctx).First(&answer, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnswerNotFound
+		}
+		return nil, err
+	}
+	return &answer, nil
+}
+
+func (r *gormAnswerRepository) Vote(ctx context.Context, id uint, userID string, value, version int) (int, error) {
+	return r.vote(ctx, id, userID, value, version)
+}
+
+func (r *gormAnswerRepository) Unvote(ctx context.Context, id uint, userID string, version int) (int, error) {
+	return r.vote(ctx, id, userID, 0, version)
+}
+
+// vote sets the vote of userID for an answer to value, zero withdraws it, keeping the score consistent in one transaction.
+func (r *gormAnswerRepository) vote(ctx context.Context, id uint, userID string, value, version int) (int, error) {
+	var answer model.Answer
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "question_id", "score", "version").First(&answer, id).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(answer.Version, version); err != nil {
+			return err
+		}
+
+		delta, err := applyVote(tx, model.Vote{UserID: userID, AnswerID: &answer.ID}, value)
+		if err != nil || delta == 0 {
+			return err
+		}
+
+		answer.Score += delta
+		err = tx.Model(&answer).UpdateColumns(map[string]any{
+			"score":   gorm.Expr("score + ?", delta),
+			"version": gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return touchQuestion(tx, answer.QuestionID)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrAnswerNotFound
+		}
+		return 0, err
+	}
+	return answer.Score, nil
+}
+
+func (r *gormAnswerRepository) Update(ctx context.Context, id uint, text, editorID string, version int) (*model.Answer, error) {
+	var answer model.Answer
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&answer, id).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(answer.Version, version); err != nil {
+			return err
+		}
+		if answer.Text == text {
+			return nil
+		}
+
+		revision := model.Revision{AnswerID: &answer.ID, Text: answer.Text, EditorID: editorID}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		answer.Text = text
+		answer.Version++
+		if err := tx.Model(&answer).Updates(map[string]any{"text": text, "version": answer.Version}).Error; err != nil {
+			return err
+		}
+		return touchQuestion(tx, answer.QuestionID)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnswerNotFound
+		}
+		return nil, err
+	}
+	return &answer, nil
+}
+
+func (r *gormAnswerRepository) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	db := r.db.WithContext(ctx)
+	if err := db.Select("id").First(&model.Answer{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnswerNotFound
+		}
+		return nil, err
+	}
+
+	var revisions []model.Revision
+	err := db.Where("answer_id = ?", id).Order("created_at, id").Find(&revisions).Error
+	return revisions, err
+}
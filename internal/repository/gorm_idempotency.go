@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormIdempotencyRepository works with any database supported by gorm whose schema was created by the migrations.
+type gormIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresIdempotencyRepository creates IdempotencyRepository instance which interacts with PostgreSQL database
+func NewPostgresIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &gormIdempotencyRepository{db: db}
+}
+
+// NewSQLiteIdempotencyRepository creates IdempotencyRepository instance which interacts with SQLite database
+func NewSQLiteIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &gormIdempotencyRepository{db: db}
+}
+
+func (r *gormIdempotencyRepository) Create(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	created := *record
+	created.ExpiresAt = created.ExpiresAt.UTC()
+
+	var existing model.IdempotencyRecord
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// An expired record is removed first so that the new one can take its place.
+		err := tx.Where("user_id = ? AND idempotency_key = ? AND expires_at <= ?", record.UserID, record.Key, time.Now().UTC()).
+			Delete(&model.IdempotencyRecord{}).Error
+		if err != nil {
+			return err
+		}
+
+		// A concurrent request with the same key makes the insert wait for it and then do nothing.
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&created)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+
+		err = tx.Where("user_id = ? AND idempotency_key = ?", record.UserID, record.Key).First(&existing).Error
+		if err != nil {
+			return err
+		}
+		return ErrIdempotencyKeyExists
+	})
+	if errors.Is(err, ErrIdempotencyKeyExists) {
+		return &existing, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *gormIdempotencyRepository) Complete(ctx context.Context, userID, key string, statusCode int, headers map[string]string, body []byte, expiresAt time.Time) error {
+	// Updating from a struct applies the JSON serializer of Headers. Select makes it write the zero values too.
+	result := r.db.WithContext(ctx).Model(&model.IdempotencyRecord{}).
+		Where("user_id = ? AND idempotency_key = ?", userID, key).
+		Select("status_code", "headers", "body", "expires_at").
+		Updates(&model.IdempotencyRecord{StatusCode: statusCode, Headers: headers, Body: body, ExpiresAt: expiresAt.UTC()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrIdempotencyKeyNotFound
+	}
+	return nil
+}
+
+func (r *gormIdempotencyRepository) Delete(ctx context.Context, userID, key string) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND idempotency_key = ?", userID, key).
+		Delete(&model.IdempotencyRecord{}).Error
+}
+
+func (r *gormIdempotencyRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", before.UTC()).Delete(&model.IdempotencyRecord{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormQuestionRepository works with any database supported by gorm whose schema was created by the migrations.
+type gormQuestionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresQuestionRepository creates QuestionRepository instance which interacts with PostgreSQL database
+func NewPostgresQuestionRepository(db *gorm.DB) QuestionRepository {
+	return &gormQuestionRepository{db: db}
+}
+
+// NewSQLiteQuestionRepository creates QuestionRepository instance which interacts with SQLite database
+func NewSQLiteQuestionRepository(db *gorm.DB) QuestionRepository {
+	return &gormQuestionRepository{db: db}
+}
+
+func (r *gormQuestionRepository) Create(ctx context.Context, question *model.Question) (*model.Question, error) {
+	if len(question.Tags) == 0 {
+		return question, r.db.WithContext(ctx).Create(question).Error
+	}
+
+	return question, r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		names := make([]string, len(question.Tags))
+		for i, tag := range question.Tags {
+			names[i] = tag.Name
+		}
+
+		if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+			Create(&question.Tags).Error; err != nil {
+			return err
+		}
+		var tags []model.Tag
+		if err := tx.Where("name IN ?", names).Order("name").Find(&tags).Error; err != nil {
+			return err
+		}
+
+		question.Tags = tags
+		return tx.Create(question).Error
+	})
+}
+
+func (r *gormQuestionRepository) Delete(ctx context.Context, id uint, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var question model.Question
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "version").First(&question, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrQuestionNotFound
+			}
+			return err
+		}
+		if err := checkVersion(question.Version, version); err != nil {
+			return err
+		}
+
+		now := tx.NowFunc()
+		if err := tx.Model(&question).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Answer{}).Where("question_id = ?", id).Update("deleted_at", now).Error
+	})
+}
+
+func (r *gormQuestionRepository) Restore(ctx context.Context, id uint, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var question model.Question
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrQuestionNotFound
+			}
+			return err
+		}
+		if err := checkVersion(question.Version, version); err != nil {
+			return err
+		}
+		if !question.DeletedAt.Valid {
+			return nil
+		}
+
+		err := tx.Unscoped().Model(&model.Answer{}).
+			Where("question_id = ? AND deleted_at = ?", id, question.DeletedAt.Time).
+			Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&question).Update("deleted_at", nil).Error
+	})
+}
+
+func (r *gormQuestionRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before.UTC()).Delete(&model.Question{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormQuestionRepository) GetByID(ctx context.Context, id uint) (*model.Question, error) {
+	question, err := findQuestion(r.db.WithContext(ctx), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	return question, nil
+}
+
+// findQuestion loads the question with its tags and its answers ordered by score.
+func findQuestion(db *gorm.DB, id uint) (*model.Question, error) {
+	var question model.Question
+	err := db.Preload("Answers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("score DESC, created_at, id")
+	}).Preload("Tags", orderTags).First(&question, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+func (r *gormQuestionRepository) Vote(ctx context.Context, id uint, userID string, value, version int) (int, error) {
+	return r.vote(ctx, id, userID, value, version)
+}
+
+func (r *gormQuestionRepository) Unvote(ctx context.Context, id uint, userID string, version int) (int, error) {
+	return r.vote(ctx, id, userID, 0, version)
+}
+
+// vote sets the vote of userID for a question to value, zero withdraws it, keeping the score consistent in one transaction.
+func (r *gormQuestionRepository) vote(ctx context.Context, id uint, userID string, value, version int) (int, error) {
+	var question model.Question
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "score", "version").First(&question, id).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(question.Version, version); err != nil {
+			return err
+		}
+
+		delta, err := applyVote(tx, model.Vote{UserID: userID, QuestionID: &question.ID}, value)
+		if err != nil || delta == 0 {
+			return err
+		}
+
+		question.Score += delta
+		return tx.Model(&question).UpdateColumns(map[string]any{
+			"score":   gorm.Expr("score + ?", delta),
+			"version": gorm.Expr("version + 1"),
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrQuestionNotFound
+		}
+		return 0, err
+	}
+	return question.Score, nil
+}
+
+func (r *gormQuestionRepository) Accept(ctx context.Context, id, answerID uint, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var question model.Question
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "accepted_answer_id", "version").First(&question, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrQuestionNotFound
+			}
+			return err
+		}
+		if err := checkVersion(question.Version, version); err != nil {
+			return err
+		}
+
+		if err := tx.Select("id").Where("question_id = ?", id).First(&model.Answer{}, answerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAnswerNotFound
+			}
+			return err
+		}
+		if question.AcceptedAnswerID != nil && *question.AcceptedAnswerID == answerID {
+			return nil
+		}
+		return tx.Model(&question).UpdateColumns(map[string]any{
+			"accepted_answer_id": answerID,
+			"version":            gorm.Expr("version + 1"),
+		}).Error
+	})
+}
+
+func (r *gormQuestionRepository) Update(ctx context.Context, id uint, text, editorID string, version int) (*model.Question, error) {
+	var updated *model.Question
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var question model.Question
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, id).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(question.Version, version); err != nil {
+			return err
+		}
+
+		if question.Text != text {
+			revision := model.Revision{QuestionID: &question.ID, Text: question.Text, EditorID: editorID}
+			if err := tx.Create(&revision).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&question).Updates(map[string]any{"text": text, "version": question.Version + 1}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Reload the question so it is returned in the same shape as by GetByID.
+		var err error
+		updated, err = findQuestion(tx, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *gormQuestionRepository) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	db := r.db.WithContext(ctx)
+	if err := db.Select("id").First(&model.Question{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+
+	var revisions []model.Revision
+	err := db.Where("question_id = ?", id).Order("created_at, id").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *gormQuestionRepository) GetAll(ctx context.Context, query QuestionQuery) ([]model.Question, error) {
+	var questions []model.Question
+	db := r.db.WithContext(ctx).Preload("Tags", orderTags).Order("created_at, id").Limit(query.Limit)
+	if query.After != nil {
+		db = db.Where("(created_at, id) > (?, ?)", query.After.CreatedAt.UTC(), query.After.ID)
+	}
+	if len(query.Tags) > 0 {
+		tagged := r.db.WithContext(ctx).Table("question_tags").
+			Select("question_tags.question_id").
+			Joins("JOIN tags ON tags.id = question_tags.tag_id").
+			Where("tags.name IN ?", query.Tags)
+		if query.MatchAllTags {
+			tagged = tagged.Group("question_tags.question_id").Having("COUNT(*) = ?", len(query.Tags))
+		}
+		db = db.Where("id IN (?)", tagged)
+	}
+	err := db.Find(&questions).Error
+	return questions, err
+}
+
+func (r *gormQuestionRepository) GetTags(ctx context.Context) ([]model.TagCount, error) {
+	var tags []model.TagCount
+	err := r.db.WithContext(ctx).Table("tags").
+		Select("tags.name, COUNT(*) AS count").
+		Joins("JOIN question_tags ON question_tags.tag_id = tags.id").
+		Joins("JOIN questions ON questions.id = question_tags.question_id AND questions.deleted_at IS NULL").
+		Group("tags.name").
+		Order("count DESC, tags.name").
+		Scan(&tags).Error
+	return tags, err
+}
+
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
+
+// touchQuestion increments the version of a question whose answers changed. Soft-deleted questions are touched as well.
+func touchQuestion(tx *gorm.DB, id uint) error {
+	return tx.Unscoped().Model(&model.Question{}).Where("id = ?", id).UpdateColumn("version", gorm.Expr("version + 1")).Error
+}
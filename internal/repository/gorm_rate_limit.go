@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormRateLimitRepository works with any database supported by gorm whose schema was created by the migrations.
+type gormRateLimitRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRateLimitRepository creates RateLimitRepository instance which interacts with PostgreSQL database
+func NewPostgresRateLimitRepository(db *gorm.DB) RateLimitRepository {
+	return &gormRateLimitRepository{db: db}
+}
+
+// NewSQLiteRateLimitRepository creates RateLimitRepository instance which interacts with SQLite database
+func NewSQLiteRateLimitRepository(db *gorm.DB) RateLimitRepository {
+	return &gormRateLimitRepository{db: db}
+}
+
+func (r *gormRateLimitRepository) Take(ctx context.Context, key string, limit model.RateLimit, now time.Time) (*model.RateLimitBucket, bool, error) {
+	now = now.UTC()
+
+	var (
+		bucket model.RateLimitBucket
+		taken  bool
+	)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A concurrent request creating the same bucket makes the insert wait for it and then do nothing.
+		full := model.RateLimitBucket{Key: key, Tokens: float64(limit.Burst), RefilledAt: now, ExpiresAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&full).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("bucket_key = ?", key).First(&bucket).Error; err != nil {
+			return err
+		}
+		taken = takeToken(&bucket, limit, now)
+		return tx.Model(&bucket).Updates(map[string]any{
+			"tokens":      bucket.Tokens,
+			"refilled_at": bucket.RefilledAt,
+			"expires_at":  bucket.ExpiresAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &bucket, taken, nil
+}
+
+func (r *gormRateLimitRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", before.UTC()).Delete(&model.RateLimitBucket{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// gormUserRepository works with any database supported by gorm whose schema was created by the migrations.
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresUserRepository creates UserRepository instance which interacts with PostgreSQL database
+func NewPostgresUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+// NewSQLiteUserRepository creates UserRepository instance which interacts with SQLite database
+func NewSQLiteUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+func (r *gormUserRepository) GetByID(ctx context.Context, id string) (*model.User, error) {
+	var user model.User
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
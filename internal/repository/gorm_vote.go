@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// applyVote sets the vote identified by the UserID and the target of key to value, removing it when value is zero,
+// and returns the resulting change of the target's score. The target row must be locked by the caller's transaction.
+func applyVote(tx *gorm.DB, key model.Vote, value int) (int, error) {
+	var vote model.Vote
+	err := tx.Where(&key).First(&vote).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	exists := err == nil
+
+	switch {
+	case !exists && value == 0:
+		return 0, nil
+	case !exists:
+		key.Value = value
+		return value, tx.Create(&key).Error
+	case value == 0:
+		return -vote.Value, tx.Delete(&vote).Error
+	case vote.Value == value:
+		return 0, nil
+	default:
+		// Update writes the new value back into vote, so the delta must be taken first.
+		delta := value - vote.Value
+		return delta, tx.Model(&vote).Update("value", value).Error
+	}
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MemoryStore holds the data shared by in-memory repositories, playing the role of a database.
+// Repositories created over the same store see each other's changes, e.g. deleting a question deletes its answers.
+// It is safe for concurrent use.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	questions map[uint]*model.Question
+	answers   map[uint]*model.Answer
+	revisions []model.Revision
+	votes     map[memoryVoteKey]int
+	tags      map[string]model.Tag
+
+	idempotencyRecords map[memoryIdempotencyKey]model.IdempotencyRecord
+
+	lastQuestionID uint
+	lastAnswerID   uint
+	lastRevisionID uint
+	lastTagID      uint
+}
+
+// memoryVoteKey identifies a vote of a user for a question or an answer, exactly one of the IDs is non-zero.
+type memoryVoteKey struct {
+	userID     string
+	questionID uint
+	answerID   uint
+}
+
+// memoryIdempotencyKey identifies an idempotency record of a user.
+type memoryIdempotencyKey struct {
+	userID string
+	key    string
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		questions: make(map[uint]*model.Question),
+		answers:   make(map[uint]*model.Answer),
+		votes:     make(map[memoryVoteKey]int),
+		tags:      make(map[string]model.Tag),
+
+		idempotencyRecords: make(map[memoryIdempotencyKey]model.IdempotencyRecord),
+	}
+}
+
+// now returns the current time with the microsecond precision of PostgreSQL timestamps.
+func (s *MemoryStore) now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// question returns a question which is not deleted.
+func (s *MemoryStore) question(id uint) (*model.Question, bool) {
+	question, ok := s.questions[id]
+	if !ok || question.DeletedAt.Valid {
+		return nil, false
+	}
+	return question, true
+}
+
+// answer returns an answer which is not deleted.
+func (s *MemoryStore) answer(id uint) (*model.Answer, bool) {
+	answer, ok := s.answers[id]
+	if !ok || answer.DeletedAt.Valid {
+		return nil, false
+	}
+	return answer, true
+}
+
+// touchQuestion is the in-memory counterpart of touchQuestion: it increments the version of a question whose answers changed.
+func (s *MemoryStore) touchQuestion(id uint) {
+	if question, ok := s.questions[id]; ok {
+		question.Version++
+	}
+}
+
+// tag returns the tag with the given name, creating it if it does not exist yet.
+func (s *MemoryStore) tag(name string) model.Tag {
+	tag, ok := s.tags[name]
+	if !ok {
+		s.lastTagID++
+		tag = model.Tag{ID: s.lastTagID, Name: name, CreatedAt: s.now()}
+		s.tags[name] = tag
+	}
+	return tag
+}
+
+// addRevision records the replaced text of a question or an answer.
+func (s *MemoryStore) addRevision(revision model.Revision) {
+	revision = cloneRevision(revision)
+	s.lastRevisionID++
+	revision.ID = s.lastRevisionID
+	revision.CreatedAt = s.now()
+	s.revisions = append(s.revisions, revision)
+}
+
+// revisionsOf returns revisions matching the filter in the order they were recorded.
+func (s *MemoryStore) revisionsOf(match func(model.Revision) bool) []model.Revision {
+	var revisions []model.Revision
+	for _, revision := range s.revisions {
+		if match(revision) {
+			revisions = append(revisions, cloneRevision(revision))
+		}
+	}
+	return revisions
+}
+
+// applyVote is the in-memory counterpart of applyVote: it sets the vote identified by key to value,
+// removing it when value is zero, and returns the resulting change of the target's score.
+func (s *MemoryStore) applyVote(key memoryVoteKey, value int) int {
+	previous := s.votes[key]
+	if value == 0 {
+		delete(s.votes, key)
+	} else {
+		s.votes[key] = value
+	}
+	return value - previous
+}
+
+// removeAnswer permanently removes an answer along with its votes and revisions.
+func (s *MemoryStore) removeAnswer(id uint) {
+	delete(s.answers, id)
+	for key := range s.votes {
+		if key.answerID == id {
+			delete(s.votes, key)
+		}
+	}
+	s.revisions = slices.DeleteFunc(s.revisions, func(revision model.Revision) bool {
+		return revision.AnswerID != nil && *revision.AnswerID == id
+	})
+	for _, question := range s.questions {
+		if question.AcceptedAnswerID != nil && *question.AcceptedAnswerID == id {
+			question.AcceptedAnswerID = nil
+		}
+	}
+}
+
+// removeQuestion permanently removes a question along with its answers, votes and revisions.
+func (s *MemoryStore) removeQuestion(id uint) {
+	for answerID, answer := range s.answers {
+		if answer.QuestionID == id {
+			s.removeAnswer(answerID)
+		}
+	}
+	delete(s.questions, id)
+	for key := range s.votes {
+		if key.questionID == id {
+			delete(s.votes, key)
+		}
+	}
+	s.revisions = slices.DeleteFunc(s.revisions, func(revision model.Revision) bool {
+		return revision.QuestionID != nil && *revision.QuestionID == id
+	})
+}
+
+// softDelete marks a record deleted at the given time.
+func softDelete(at time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: at, Valid: true}
+}
+
+// cloneQuestion copies a question so that callers cannot modify the stored one.
+// Answers are not copied, they are embedded by the caller when needed.
+func cloneQuestion(question *model.Question) *model.Question {
+	clone := *question
+	clone.Answers = nil
+	clone.Tags = slices.Clone(question.Tags)
+	if question.AcceptedAnswerID != nil {
+		id := *question.AcceptedAnswerID
+		clone.AcceptedAnswerID = &id
+	}
+	return &clone
+}
+
+func cloneAnswer(answer *model.Answer) *model.Answer {
+	clone := *answer
+	return &clone
+}
+
+func cloneRevision(revision model.Revision) model.Revision {
+	if revision.QuestionID != nil {
+		id := *revision.QuestionID
+		revision.QuestionID = &id
+	}
+	if revision.AnswerID != nil {
+		id := *revision.AnswerID
+		revision.AnswerID = &id
+	}
+	return revision
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+)
+
+type memoryAnswerRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryAnswerRepository creates AnswerRepository instance which keeps answers in memory
+func NewMemoryAnswerRepository(store *MemoryStore) AnswerRepository {
+	return &memoryAnswerRepository{store: store}
+}
+
+func (r *memoryAnswerRepository) Create(ctx context.Context, answer *model.Answer) (*model.Answer, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirrors the foreign key, which does not care whether the question is soft-deleted.
+	if _, ok := r.store.questions[answer.QuestionID]; !ok {
+		return nil, ErrQuestionNotFound
+	}
+
+	r.store.lastAnswerID++
+	answer.ID = r.store.lastAnswerID
+	if answer.CreatedAt.IsZero() {
+		answer.CreatedAt = r.store.now()
+	}
+	if answer.Version == 0 {
+		answer.Version = 1
+	}
+
+	r.store.answers[answer.ID] = cloneAnswer(answer)
+	r.store.touchQuestion(answer.QuestionID)
+	return answer, nil
+}
+
+func (r *memoryAnswerRepository) Delete(ctx context.Context, id uint, version int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	answer, ok := r.store.answer(id)
+	if !ok {
+		return ErrAnswerNotFound
+	}
+	if err := checkVersion(answer.Version, version); err != nil {
+		return err
+	}
+
+	answer.DeletedAt = softDelete(r.store.now())
+	for _, question := range r.store.questions {
+		if question.AcceptedAnswerID != nil && *question.AcceptedAnswerID == id {
+			question.AcceptedAnswerID = nil
+		}
+	}
+	r.store.touchQuestion(answer.QuestionID)
+	return nil
+}
+
+func (r *memoryAnswerRepository) Restore(ctx context.Context, id uint, version int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	answer, ok := r.store.answers[id]
+	if !ok {
+		return ErrAnswerNotFound
+	}
+	if err := checkVersion(answer.Version, version); err != nil {
+		return err
+	}
+	if !answer.DeletedAt.Valid {
+		return nil
+	}
+
+	if _, ok := r.store.question(answer.QuestionID); !ok {
+		return ErrQuestionNotFound
+	}
+	answer.DeletedAt.Valid = false
+	r.store.touchQuestion(answer.QuestionID)
+	return nil
+}
+
+func (r *memoryAnswerRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, answer := range r.store.answers {
+		if answer.DeletedAt.Valid && answer.DeletedAt.Time.Before(before) {
+			r.store.removeAnswer(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *memoryAnswerRepository) GetByID(ctx context.Context, id uint) (*model.Answer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	answer, ok := r.store.answer(id)
+	if !ok {
+		return nil, ErrAnswerNotFound
+	}
+	return cloneAnswer(answer), nil
+}
+
+func (r *memoryAnswerRepository) Vote(ctx context.Context, id uint, userID string, value, version int) (int, error) {
+	return r.vote(id, userID, value, version)
+}
+
+func (r *memoryAnswerRepository) Unvote(ctx context.Context, id uint, userID string, version int) (int, error) {
+	return r.vote(id, userID, 0, version)
+}
+
+func (r *memoryAnswerRepository) vote(id uint, userID string, value, version int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	answer, ok := r.store.answer(id)
+	if !ok {
+		return 0, ErrAnswerNotFound
+	}
+	if err := checkVersion(answer.Version, version); err != nil {
+		return 0, err
+	}
+
+	if delta := r.store.applyVote(memoryVoteKey{userID: userID, answerID: id}, value); delta != 0 {
+		answer.Score += delta
+		answer.Version++
+		r.store.touchQuestion(answer.QuestionID)
+	}
+	return answer.Score, nil
+}
+
+func (r *memoryAnswerRepository) Update(ctx context.Context, id uint, text, editorID string, version int) (*model.Answer, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	answer, ok := r.store.answer(id)
+	if !ok {
+		return nil, ErrAnswerNotFound
+	}
+	if err := checkVersion(answer.Version, version); err != nil {
+		return nil, err
+	}
+
+	if answer.Text != text {
+		r.store.addRevision(model.Revision{AnswerID: &answer.ID, Text: answer.Text, EditorID: editorID})
+		answer.Text = text
+		answer.Version++
+		r.store.touchQuestion(answer.QuestionID)
+	}
+	return cloneAnswer(answer), nil
+}
+
+func (r *memoryAnswerRepository) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.answer(id); !ok {
+		return nil, ErrAnswerNotFound
+	}
+
+	return r.store.revisionsOf(func(revision model.Revision) bool {
+		return revision.AnswerID != nil && *revision.AnswerID == id
+	}), nil
+}
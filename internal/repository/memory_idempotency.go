@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+)
+
+type memoryIdempotencyRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryIdempotencyRepository creates IdempotencyRepository instance which keeps records in the given store
+func NewMemoryIdempotencyRepository(store *MemoryStore) IdempotencyRepository {
+	return &memoryIdempotencyRepository{store: store}
+}
+
+func (r *memoryIdempotencyRepository) Create(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := memoryIdempotencyKey{userID: record.UserID, key: record.Key}
+	now := r.store.now()
+	if existing, ok := r.store.idempotencyRecords[key]; ok && existing.ExpiresAt.After(now) {
+		return cloneIdempotencyRecord(existing), ErrIdempotencyKeyExists
+	}
+
+	created := *record
+	created.Headers = maps.Clone(record.Headers)
+	created.Body = slices.Clone(record.Body)
+	created.CreatedAt = now
+	created.ExpiresAt = created.ExpiresAt.Truncate(time.Microsecond)
+	r.store.idempotencyRecords[key] = created
+	return cloneIdempotencyRecord(created), nil
+}
+
+func (r *memoryIdempotencyRepository) Complete(ctx context.Context, userID, key string, statusCode int, headers map[string]string, body []byte, expiresAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	recordKey := memoryIdempotencyKey{userID: userID, key: key}
+	record, ok := r.store.idempotencyRecords[recordKey]
+	if !ok {
+		return ErrIdempotencyKeyNotFound
+	}
+	record.StatusCode = statusCode
+	record.Headers = maps.Clone(headers)
+	record.Body = slices.Clone(body)
+	record.ExpiresAt = expiresAt.Truncate(time.Microsecond)
+	r.store.idempotencyRecords[recordKey] = record
+	return nil
+}
+
+func (r *memoryIdempotencyRepository) Delete(ctx context.Context, userID, key string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.idempotencyRecords, memoryIdempotencyKey{userID: userID, key: key})
+	return nil
+}
+
+func (r *memoryIdempotencyRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for key, record := range r.store.idempotencyRecords {
+		if record.ExpiresAt.Before(before) {
+			delete(r.store.idempotencyRecords, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func cloneIdempotencyRecord(record model.IdempotencyRecord) *model.IdempotencyRecord {
+	record.Headers = maps.Clone(record.Headers)
+	record.Body = slices.Clone(record.Body)
+	return &record
+}
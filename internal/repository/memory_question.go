@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+)
+
+type memoryQuestionRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryQuestionRepository creates QuestionRepository instance which keeps questions in memory
+func NewMemoryQuestionRepository(store *MemoryStore) QuestionRepository {
+	return &memoryQuestionRepository{store: store}
+}
+
+func (r *memoryQuestionRepository) Create(ctx context.Context, question *model.Question) (*model.Question, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.lastQuestionID++
+	question.ID = r.store.lastQuestionID
+	if question.CreatedAt.IsZero() {
+		question.CreatedAt = r.store.now()
+	}
+	if question.Version == 0 {
+		question.Version = 1
+	}
+
+	tags := make([]model.Tag, len(question.Tags))
+	for i, tag := range question.Tags {
+		tags[i] = r.store.tag(tag.Name)
+	}
+	slices.SortFunc(tags, func(a, b model.Tag) int { return cmp.Compare(a.Name, b.Name) })
+	question.Tags = tags
+
+	r.store.questions[question.ID] = cloneQuestion(question)
+	return question, nil
+}
+
+func (r *memoryQuestionRepository) Delete(ctx context.Context, id uint, version int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	question, ok := r.store.question(id)
+	if !ok {
+		return ErrQuestionNotFound
+	}
+	if err := checkVersion(question.Version, version); err != nil {
+		return err
+	}
+
+	now := r.store.now()
+	question.DeletedAt = softDelete(now)
+	for _, answer := range r.store.answers {
+		if answer.QuestionID == id && !answer.DeletedAt.Valid {
+			answer.DeletedAt = softDelete(now)
+		}
+	}
+	return nil
+}
+
+func (r *memoryQuestionRepository) Restore(ctx context.Context, id uint, version int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	question, ok := r.store.questions[id]
+	if !ok {
+		return ErrQuestionNotFound
+	}
+	if err := checkVersion(question.Version, version); err != nil {
+		return err
+	}
+	if !question.DeletedAt.Valid {
+		return nil
+	}
+
+	for _, answer := range r.store.answers {
+		if answer.QuestionID == id && answer.DeletedAt.Valid && answer.DeletedAt.Time.Equal(question.DeletedAt.Time) {
+			answer.DeletedAt.Valid = false
+		}
+	}
+	question.DeletedAt.Valid = false
+	return nil
+}
+
+func (r *memoryQuestionRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, question := range r.store.questions {
+		if question.DeletedAt.Valid && question.DeletedAt.Time.Before(before) {
+			r.store.removeQuestion(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *memoryQuestionRepository) GetByID(ctx context.Context, id uint) (*model.Question, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	question, ok := r.store.question(id)
+	if !ok {
+		return nil, ErrQuestionNotFound
+	}
+	return r.withAnswers(question), nil
+}
+
+// withAnswers clones the question along with its answers ordered by score. The store must be locked by the caller.
+func (r *memoryQuestionRepository) withAnswers(stored *model.Question) *model.Question {
+	question := cloneQuestion(stored)
+	for _, answer := range r.store.answers {
+		if answer.QuestionID == stored.ID && !answer.DeletedAt.Valid {
+			question.Answers = append(question.Answers, *cloneAnswer(answer))
+		}
+	}
+	slices.SortFunc(question.Answers, func(a, b model.Answer) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return question
+}
+
+func (r *memoryQuestionRepository) Vote(ctx context.Context, id uint, userID string, value, version int) (int, error) {
+	return r.vote(id, userID, value, version)
+}
+
+func (r *memoryQuestionRepository) Unvote(ctx context.Context, id uint, userID string, version int) (int, error) {
+	return r.vote(id, userID, 0, version)
+}
+
+func (r *memoryQuestionRepository) vote(id uint, userID string, value, version int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	question, ok := r.store.question(id)
+	if !ok {
+		return 0, ErrQuestionNotFound
+	}
+	if err := checkVersion(question.Version, version); err != nil {
+		return 0, err
+	}
+
+	if delta := r.store.applyVote(memoryVoteKey{userID: userID, questionID: id}, value); delta != 0 {
+		question.Score += delta
+		question.Version++
+	}
+	return question.Score, nil
+}
+
+func (r *memoryQuestionRepository) Accept(ctx context.Context, id, answerID uint, version int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	question, ok := r.store.question(id)
+	if !ok {
+		return ErrQuestionNotFound
+	}
+	if err := checkVersion(question.Version, version); err != nil {
+		return err
+	}
+
+	answer, ok := r.store.answer(answerID)
+	if !ok || answer.QuestionID != id {
+		return ErrAnswerNotFound
+	}
+
+	if question.AcceptedAnswerID == nil || *question.AcceptedAnswerID != answerID {
+		question.AcceptedAnswerID = &answerID
+		question.Version++
+	}
+	return nil
+}
+
+func (r *memoryQuestionRepository) Update(ctx context.Context, id uint, text, editorID string, version int) (*model.Question, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	question, ok := r.store.question(id)
+	if !ok {
+		return nil, ErrQuestionNotFound
+	}
+	if err := checkVersion(question.Version, version); err != nil {
+		return nil, err
+	}
+
+	if question.Text != text {
+		r.store.addRevision(model.Revision{QuestionID: &question.ID, Text: question.Text, EditorID: editorID})
+		question.Text = text
+		question.Version++
+	}
+
+	return r.withAnswers(question), nil
+}
+
+func (r *memoryQuestionRepository) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.question(id); !ok {
+		return nil, ErrQuestionNotFound
+	}
+
+	return r.store.revisionsOf(func(revision model.Revision) bool {
+		return revision.QuestionID != nil && *revision.QuestionID == id
+	}), nil
+}
+
+func (r *memoryQuestionRepository) GetAll(ctx context.Context, query QuestionQuery) ([]model.Question, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var questions []model.Question
+	for _, question := range r.store.questions {
+		if question.DeletedAt.Valid {
+			continue
+		}
+		if query.After != nil && compareQuestionPosition(question, query.After) <= 0 {
+			continue
+		}
+		if !hasTags(question, query.Tags, query.MatchAllTags) {
+			continue
+		}
+		questions = append(questions, *cloneQuestion(question))
+	}
+
+	slices.SortFunc(questions, func(a, b model.Question) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	if len(questions) > query.Limit {
+		questions = questions[:query.Limit]
+	}
+	return questions, nil
+}
+
+func (r *memoryQuestionRepository) GetTags(ctx context.Context) ([]model.TagCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, question := range r.store.questions {
+		if question.DeletedAt.Valid {
+			continue
+		}
+		for _, tag := range question.Tags {
+			counts[tag.Name]++
+		}
+	}
+
+	var tags []model.TagCount
+	for name, count := range counts {
+		tags = append(tags, model.TagCount{Name: name, Count: count})
+	}
+	slices.SortFunc(tags, func(a, b model.TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Name, b.Name))
+	})
+	return tags, nil
+}
+
+// compareQuestionPosition compares the position of a question in the (created_at, id) ordering with the cursor.
+func compareQuestionPosition(question *model.Question, cursor *Cursor) int {
+	return cmp.Or(question.CreatedAt.Compare(cursor.CreatedAt), cmp.Compare(question.ID, cursor.ID))
+}
+
+// hasTags reports whether a question is labelled with all or any of the tags. Empty tags match every question.
+func hasTags(question *model.Question, tags []string, matchAll bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	matched := 0
+	for _, tag := range question.Tags {
+		if slices.Contains(tags, tag.Name) {
+			matched++
+		}
+	}
+	if matchAll {
+		return matched == len(tags)
+	}
+	return matched > 0
+}
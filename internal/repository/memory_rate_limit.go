@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+)
+
+type memoryRateLimitRepository struct {
+	mu      sync.Mutex
+	buckets map[string]model.RateLimitBucket
+}
+
+// NewMemoryRateLimitRepository creates RateLimitRepository instance which keeps buckets in memory of the process.
+// Every replica of the API has its own buckets, so a client may exceed a limit by spreading requests among them.
+func NewMemoryRateLimitRepository() RateLimitRepository {
+	return &memoryRateLimitRepository{buckets: make(map[string]model.RateLimitBucket)}
+}
+
+func (r *memoryRateLimitRepository) Take(ctx context.Context, key string, limit model.RateLimit, now time.Time) (*model.RateLimitBucket, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = model.RateLimitBucket{Key: key, Tokens: float64(limit.Burst), RefilledAt: now}
+	}
+	taken := takeToken(&bucket, limit, now)
+	r.buckets[key] = bucket
+	return &bucket, taken, nil
+}
+
+func (r *memoryRateLimitRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for key, bucket := range r.buckets {
+		if bucket.ExpiresAt.Before(before) {
+			delete(r.buckets, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
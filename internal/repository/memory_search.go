@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/ppb03/qna-api/internal/model"
+)
+
+type memorySearchRepository struct {
+	store *MemoryStore
+}
+
+// NewMemorySearchRepository creates SearchRepository instance which scans questions and answers kept in memory.
+// It matches texts containing every word of the query case-insensitively and does not support the websearch syntax.
+func NewMemorySearchRepository(store *MemoryStore) SearchRepository {
+	return &memorySearchRepository{store: store}
+}
+
+func (r *memorySearchRepository) Search(ctx context.Context, query string, limit int) ([]model.SearchHit, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var hits []model.SearchHit
+	for _, question := range r.store.questions {
+		if question.DeletedAt.Valid {
+			continue
+		}
+		if rank, snippet, ok := matchTerms(question.Text, terms); ok {
+			hits = append(hits, model.SearchHit{
+				Type: model.SearchHitQuestion, ID: question.ID, QuestionID: question.ID, Rank: rank, Snippet: snippet,
+			})
+		}
+	}
+	for _, answer := range r.store.answers {
+		if answer.DeletedAt.Valid {
+			continue
+		}
+		if rank, snippet, ok := matchTerms(answer.Text, terms); ok {
+			hits = append(hits, model.SearchHit{
+				Type: model.SearchHitAnswer, ID: answer.ID, QuestionID: answer.QuestionID, Rank: rank, Snippet: snippet,
+			})
+		}
+	}
+
+	return topHits(hits, limit), nil
+}
+
+// searchTerms splits a query into distinct lowercase words.
+func searchTerms(query string) []string {
+	return slices.Compact(slices.Sorted(slices.Values(strings.Fields(strings.ToLower(query)))))
+}
+
+// topHits orders hits like the PostgreSQL implementation does and keeps at most limit of them.
+func topHits(hits []model.SearchHit, limit int) []model.SearchHit {
+	slices.SortFunc(hits, func(a, b model.SearchHit) int {
+		return cmp.Or(cmp.Compare(b.Rank, a.Rank), cmp.Compare(b.Type, a.Type), cmp.Compare(a.ID, b.ID))
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// matchTerms reports whether text contains every one of distinct terms as a word. The rank is the share of words that matched
+// and the snippet is the HTML-escaped text with matched words wrapped in <mark> tags.
+func matchTerms(text string, terms []string) (float64, string, bool) {
+	words := strings.Fields(text)
+	found := make(map[string]bool, len(terms))
+	matched := 0
+	for i, word := range words {
+		normalized := strings.ToLower(strings.Trim(word, ".,!?;:()\"'"))
+		if slices.Contains(terms, normalized) {
+			found[normalized] = true
+			matched++
+			words[i] = "<mark>" + html.EscapeString(word) + "</mark>"
+		} else {
+			words[i] = html.EscapeString(word)
+		}
+	}
+	if len(found) < len(terms) {
+		return 0, "", false
+	}
+	return float64(matched) / float64(len(words)), strings.Join(words, " "), true
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ppb03/qna-api/internal/model"
+)
+
+type memoryUserRepository struct {
+	users map[string]model.User
+}
+
+// NewMemoryUserRepository creates UserRepository instance for in-memory storage which knows the given users only.
+// In-memory storage has no way to assign roles at runtime, so any other user is treated as having model.RoleUser.
+func NewMemoryUserRepository(users ...model.User) UserRepository {
+	r := memoryUserRepository{users: make(map[string]model.User, len(users))}
+	for _, user := range users {
+		r.users[user.ID] = user
+	}
+	return r
+}
+
+func (r memoryUserRepository) GetByID(ctx context.Context, id string) (*model.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &user, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// searchQuery ranks matches across both tables using the generated search_vector columns and their GIN indexes.
+// Texts are HTML-escaped the way html.EscapeString does before ts_headline marks the matches in them.
+const searchQuery = `
+SELECT 'question' AS type, q.id AS id, q.id AS question_id,
+       ts_rank(q.search_vector, query) AS rank,
+       ts_headline('simple', replace(replace(replace(replace(replace(q.text,
+           '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'), query, @headline) AS snippet
+FROM questions q, websearch_to_tsquery('simple', @query) query
+WHERE q.search_vector @@ query AND q.deleted_at IS NULL
+UNION ALL
+SELECT 'answer' AS type, a.id AS id, a.question_id AS question_id,
+       ts_rank(a.search_vector, query) AS rank,
+       ts_headline('simple', replace(replace(replace(replace(replace(a.text,
+           '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'), query, @headline) AS snippet
+FROM answers a, websearch_to_tsquery('simple', @query) query
+WHERE a.search_vector @@ query AND a.deleted_at IS NULL
+ORDER BY rank DESC, type DESC, id
+LIMIT @limit`
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+type postgresSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresSearchRepository creates SearchRepository instance which uses PostgreSQL full-text search
+func NewPostgresSearchRepository(db *gorm.DB) SearchRepository {
+	return &postgresSearchRepository{db: db}
+}
+
+func (r *postgresSearchRepository) Search(ctx context.Context, query string, limit int) ([]model.SearchHit, error) {
+	var hits []model.SearchHit
+	err := r.db.WithContext(ctx).Raw(searchQuery, map[string]any{
+		"query":    query,
+		"headline": headlineOptions,
+		"limit":    limit,
+	}).Scan(&hits).Error
+	return hits, err
+}
@@ -6,6 +6,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ppb03/qna-api/internal/model"
 )
@@ -13,35 +14,189 @@ import (
 var (
 	ErrQuestionNotFound = errors.New("no question with such ID")
 	ErrAnswerNotFound   = errors.New("no answer with such ID")
+	ErrUserNotFound     = errors.New("no user with such ID")
+	ErrVersionMismatch  = errors.New("version does not match")
+
+	ErrIdempotencyKeyExists   = errors.New("idempotency key is already in use")
+	ErrIdempotencyKeyNotFound = errors.New("no record with such idempotency key")
 )
 
+// checkVersion fails with ErrVersionMismatch unless the expected version is zero, meaning any, or equals the current one.
+func checkVersion(current, expected int) error {
+	if expected != 0 && expected != current {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// Cursor identifies a position of a question in the (created_at, id) ordering used for pagination.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// QuestionQuery describes a page of questions requested from the repository.
+type QuestionQuery struct {
+	// After restricts the result to questions positioned strictly after the cursor. Nil means the first page.
+	After *Cursor
+
+	// Limit is the maximum number of questions to return.
+	Limit int
+
+	// Tags restricts the result to questions labelled with the given tags. Empty means no restriction.
+	Tags []string
+
+	// MatchAllTags requires a question to have every tag in Tags rather than any of them.
+	MatchAllTags bool
+}
+
 // QuestionRepository defines the interface for operations related to questions on repository layer.
 //
 // Standart implementation interacts with PostgreSQL and can be obtained via NewPostgresQuestionRepository() function.
+// In-memory implementation can be obtained via NewMemoryQuestionRepository() function.
+//
+// Methods taking a version fail with ErrVersionMismatch unless it is zero or the current version of the question.
 type QuestionRepository interface {
 	// Create creates a new question and persists it to the database.
+	// Tags of the question are looked up by name and created if they do not exist yet.
 	Create(ctx context.Context, question *model.Question) (*model.Question, error)
 
-	// Delete removes a question from the database based on its ID.
-	Delete(ctx context.Context, id uint) error
+	// Delete soft-deletes a question along with its answers based on its ID.
+	Delete(ctx context.Context, id uint, version int) error
+
+	// Restore brings back a soft-deleted question along with the answers deleted together with it.
+	// Restoring a question that is not deleted is a no-op.
+	Restore(ctx context.Context, id uint, version int) error
 
-	// GetByID retrieves a question from the database based on its ID along with its associated answers.
+	// Purge permanently removes questions soft-deleted before the given time and returns their number.
+	Purge(ctx context.Context, before time.Time) (int64, error)
+
+	// GetByID retrieves a question from the database based on its ID along with its tags and associated answers sorted by score.
 	GetByID(ctx context.Context, id uint) (*model.Question, error)
 
-	// GetAll retrieves all questions from the database.
-	GetAll(ctx context.Context) ([]model.Question, error)
+	// Vote records the vote of userID for a question, replacing their previous vote, and returns the updated score.
+	Vote(ctx context.Context, id uint, userID string, value, version int) (int, error)
+
+	// Unvote withdraws the vote of userID for a question, if any, and returns the updated score.
+	Unvote(ctx context.Context, id uint, userID string, version int) (int, error)
+
+	// Accept marks answerID as the accepted answer of a question.
+	// Returns ErrAnswerNotFound if the answer does not exist or belongs to another question.
+	Accept(ctx context.Context, id, answerID uint, version int) error
+
+	// Update replaces the text of a question and records the previous text as a revision made by editorID.
+	Update(ctx context.Context, id uint, text, editorID string, version int) (*model.Question, error)
+
+	// GetRevisions retrieves prior versions of a question text ordered from oldest to newest.
+	GetRevisions(ctx context.Context, id uint) ([]model.Revision, error)
+
+	// GetAll retrieves a page of questions from the database along with their tags ordered by creation time and ID.
+	GetAll(ctx context.Context, query QuestionQuery) ([]model.Question, error)
+
+	// GetTags retrieves tags used by at least one question along with their usage counts, most used first.
+	GetTags(ctx context.Context) ([]model.TagCount, error)
 }
 
 // AnswerRepository defines the interface for operations related to answers on repository layer.
+// Changes to answers also increment the version of their question.
 //
 // Standart implementation interacts with PostgreSQL and can be obtained via NewPostgresAnswerRepository() function.
+// In-memory implementation can be obtained via NewMemoryAnswerRepository() function.
+//
+// Methods taking a version fail with ErrVersionMismatch unless it is zero or the current version of the answer.
 type AnswerRepository interface {
 	// Create creates a new answer and persists it to the database.
 	Create(ctx context.Context, answer *model.Answer) (*model.Answer, error)
 
-	// Delete removes an answer from the database based on its ID.
-	Delete(ctx context.Context, id uint) error
+	// Delete soft-deletes an answer based on its ID, clearing it as the accepted answer of its question.
+	Delete(ctx context.Context, id uint, version int) error
+
+	// Restore brings back a soft-deleted answer. It fails with ErrQuestionNotFound if the question of the answer is deleted.
+	// Restoring an answer that is not deleted is a no-op.
+	Restore(ctx context.Context, id uint, version int) error
+
+	// Purge permanently removes answers soft-deleted before the given time and returns their number.
+	Purge(ctx context.Context, before time.Time) (int64, error)
 
 	// GetByID retrieves an answer from the database based on its ID.
 	GetByID(ctx context.Context, id uint) (*model.Answer, error)
+
+	// Vote records the vote of userID for an answer, replacing their previous vote, and returns the updated score.
+	Vote(ctx context.Context, id uint, userID string, value, version int) (int, error)
+
+	// Unvote withdraws the vote of userID for an answer, if any, and returns the updated score.
+	Unvote(ctx context.Context, id uint, userID string, version int) (int, error)
+
+	// Update replaces the text of an answer and records the previous text as a revision made by editorID.
+	Update(ctx context.Context, id uint, text, editorID string, version int) (*model.Answer, error)
+
+	// GetRevisions retrieves prior versions of an answer text ordered from oldest to newest.
+	GetRevisions(ctx context.Context, id uint) ([]model.Revision, error)
+}
+
+// UserRepository defines the interface for operations related to users on repository layer.
+//
+// Standart implementation interacts with PostgreSQL and can be obtained via NewPostgresUserRepository() function.
+// In-memory implementation, which knows the users passed to it only, can be obtained via NewMemoryUserRepository() function.
+type UserRepository interface {
+	// GetByID retrieves a user from the database based on its ID.
+	GetByID(ctx context.Context, id string) (*model.User, error)
+}
+
+// SearchRepository defines the interface for full-text search over questions and answers on repository layer.
+//
+// Standart implementation interacts with PostgreSQL and can be obtained via NewPostgresSearchRepository() function.
+// In-memory implementation can be obtained via NewMemorySearchRepository() function.
+type SearchRepository interface {
+	// Search retrieves questions and answers matching the query, ordered by descending relevance.
+	Search(ctx context.Context, query string, limit int) ([]model.SearchHit, error)
+}
+
+// IdempotencyRepository defines the interface for storing requests made with idempotency keys on repository layer.
+//
+// Standart implementation interacts with PostgreSQL and can be obtained via NewPostgresIdempotencyRepository() function.
+// In-memory implementation can be obtained via NewMemoryIdempotencyRepository() function.
+type IdempotencyRepository interface {
+	// Create stores a new record unless the user already has an unexpired record with the same key,
+	// in which case it returns that record along with ErrIdempotencyKeyExists. An expired record is replaced.
+	Create(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+
+	// Complete stores the response to the request of a record and moves its expiration to expiresAt.
+	Complete(ctx context.Context, userID, key string, statusCode int, headers map[string]string, body []byte, expiresAt time.Time) error
+
+	// Delete removes a record, so that its key can be used again. Deleting a missing record is a no-op.
+	Delete(ctx context.Context, userID, key string) error
+
+	// Purge permanently removes records expired before the given time and returns their number.
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RateLimitRepository defines the interface for storing token buckets of rate limits on repository layer.
+//
+// Standart implementation interacts with PostgreSQL and can be obtained via NewPostgresRateLimitRepository() function.
+// In-memory implementation, which keeps buckets of a single process, can be obtained via NewMemoryRateLimitRepository() function.
+type RateLimitRepository interface {
+	// Take replenishes the bucket identified by key as of now according to limit and takes a token from it if there is one,
+	// atomically with respect to concurrent calls. A missing bucket is created full.
+	// It returns the bucket after the attempt and whether the token was taken.
+	Take(ctx context.Context, key string, limit model.RateLimit, now time.Time) (*model.RateLimitBucket, bool, error)
+
+	// Purge permanently removes buckets expired before the given time and returns their number.
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// takeToken replenishes the bucket as of now, takes a token from it if there is one and reports whether it did.
+func takeToken(bucket *model.RateLimitBucket, limit model.RateLimit, now time.Time) bool {
+	interval := limit.Interval()
+	if elapsed := now.Sub(bucket.RefilledAt); elapsed > 0 {
+		bucket.Tokens = min(float64(limit.Burst), bucket.Tokens+float64(elapsed)/float64(interval))
+	}
+	bucket.RefilledAt = now
+
+	taken := bucket.Tokens >= 1
+	if taken {
+		bucket.Tokens--
+	}
+	bucket.ExpiresAt = now.Add(time.Duration((float64(limit.Burst) - bucket.Tokens) * float64(interval)))
+	return taken
 }
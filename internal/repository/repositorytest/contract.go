@@ -0,0 +1,761 @@
+// Package repositorytest provides a contract test suite which every implementation of
+// repository.QuestionRepository, repository.AnswerRepository, repository.UserRepository, repository.SearchRepository,
+// repository.IdempotencyRepository and repository.RateLimitRepository is expected to pass.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Backend is a set of repositories sharing the same storage.
+type Backend struct {
+	Questions   repository.QuestionRepository
+	Answers     repository.AnswerRepository
+	Users       repository.UserRepository
+	Search      repository.SearchRepository
+	Idempotency repository.IdempotencyRepository
+	RateLimits  repository.RateLimitRepository
+}
+
+// Factory creates a Backend with empty storage apart from the users returned by Users.
+// It is called once per test and may register cleanups on t.
+type Factory func(t *testing.T) Backend
+
+const (
+	userID      = "123e4567-e89b-12d3-a456-426614174000"
+	otherUserID = "9b2f3c1e-7d4a-4e8b-a1c2-0f5e6d7c8b9a"
+	moderatorID = "5d0c8f1a-3b6e-4c2d-9f7a-1e8b2c4d6a0f"
+	adminID     = "e2a7b9c4-6f1d-4a3e-8b5c-7d9e0f1a2b3c"
+)
+
+// Users returns the users a Factory stores in every Backend, since UserRepository has no way to create them.
+func Users() []model.User {
+	return []model.User{
+		{ID: moderatorID, Role: model.RoleModerator},
+		{ID: adminID, Role: model.RoleAdmin},
+	}
+}
+
+// Run runs the contract test suite against repositories created by newBackend.
+func Run(t *testing.T, newBackend Factory) {
+	tests := map[string]func(t *testing.T, b Backend){
+		"QuestionRoundTrip":       testQuestionRoundTrip,
+		"QuestionNotFound":        testQuestionNotFound,
+		"AnswerRoundTrip":         testAnswerRoundTrip,
+		"AnswerNotFound":          testAnswerNotFound,
+		"DeleteQuestionCascades":  testDeleteQuestionCascades,
+		"RestoreQuestion":         testRestoreQuestion,
+		"RestoreAnswerOfDeleted":  testRestoreAnswerOfDeletedQuestion,
+		"Purge":                   testPurge,
+		"AnswersOrderedByScore":   testAnswersOrderedByScore,
+		"GetAllOrderingAndCursor": testGetAllOrderingAndCursor,
+		"GetAllFiltersByTags":     testGetAllFiltersByTags,
+		"Votes":                   testVotes,
+		"SwitchVote":              testSwitchVote,
+		"Accept":                  testAccept,
+		"DeleteAcceptedAnswer":    testDeleteAcceptedAnswer,
+		"UpdateRecordsRevisions":  testUpdateRecordsRevisions,
+		"UpdateLoadsAssociations": testUpdateLoadsAssociations,
+		"QuestionVersions":        testQuestionVersions,
+		"AnswerVersions":          testAnswerVersions,
+		"VersionMismatch":         testVersionMismatch,
+		"IdempotencyKeys":         testIdempotencyKeys,
+		"IdempotencyExpiration":   testIdempotencyExpiration,
+		"RateLimitBuckets":        testRateLimitBuckets,
+		"UserRoles":               testUserRoles,
+		"Search":                  testSearch,
+		"SearchEscapesSnippets":   testSearchEscapesSnippets,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, newBackend(t))
+		})
+	}
+}
+
+func createQuestion(t *testing.T, b Backend, text string, tags ...string) *model.Question {
+	t.Helper()
+
+	question := &model.Question{AuthorID: userID, Text: text}
+	for _, tag := range tags {
+		question.Tags = append(question.Tags, model.Tag{Name: tag})
+	}
+
+	question, err := b.Questions.Create(context.Background(), question)
+	require.NoError(t, err)
+	return question
+}
+
+func createAnswer(t *testing.T, b Backend, questionID uint, text string) *model.Answer {
+	t.Helper()
+
+	answer, err := b.Answers.Create(context.Background(), &model.Answer{QuestionID: questionID, UserID: userID, Text: text})
+	require.NoError(t, err)
+	return answer
+}
+
+func answerIDs(answers []model.Answer) []uint {
+	ids := make([]uint, len(answers))
+	for i, answer := range answers {
+		ids[i] = answer.ID
+	}
+	return ids
+}
+
+func questionTexts(questions []model.Question) []string {
+	texts := make([]string, len(questions))
+	for i, question := range questions {
+		texts[i] = question.Text
+	}
+	return texts
+}
+
+func testQuestionRoundTrip(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	first := createQuestion(t, b, "First question", "postgres", "go")
+	second := createQuestion(t, b, "Second question")
+	assert.NotZero(t, first.ID)
+	assert.Greater(t, second.ID, first.ID)
+	assert.WithinDuration(t, time.Now(), first.CreatedAt, time.Minute)
+
+	got, err := b.Questions.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, got.ID)
+	assert.Equal(t, userID, got.AuthorID)
+	assert.Equal(t, "First question", got.Text)
+	assert.Zero(t, got.Score)
+	assert.Nil(t, got.AcceptedAnswerID)
+	assert.True(t, first.CreatedAt.Equal(got.CreatedAt), "CreatedAt %v != %v", first.CreatedAt, got.CreatedAt)
+	assert.Empty(t, got.Answers)
+	assert.Equal(t, []model.Tag{{Name: "go"}, {Name: "postgres"}}, namesOnly(got.Tags))
+}
+
+func namesOnly(tags []model.Tag) []model.Tag {
+	names := make([]model.Tag, len(tags))
+	for i, tag := range tags {
+		names[i] = model.Tag{Name: tag.Name}
+	}
+	return names
+}
+
+func testQuestionNotFound(t *testing.T, b Backend) {
+	ctx := context.Background()
+	const missing = 999999
+
+	_, err := b.Questions.GetByID(ctx, missing)
+	assert.ErrorIs(t, err, repository.ErrQuestionNotFound)
+	assert.ErrorIs(t, b.Questions.Delete(ctx, missing, 0), repository.ErrQuestionNotFound)
+	assert.ErrorIs(t, b.Questions.Restore(ctx, missing, 0), repository.ErrQuestionNotFound)
+	_, err = b.Questions.Update(ctx, missing, "text", userID, 0)
+	assert.ErrorIs(t, err, repository.ErrQuestionNotFound)
+	_, err = b.Questions.GetRevisions(ctx, missing)
+	assert.ErrorIs(t, err, repository.ErrQuestionNotFound)
+	_, err = b.Questions.Vote(ctx, missing, userID, 1, 0)
+	assert.ErrorIs(t, err, repository.ErrQuestionNotFound)
+	assert.ErrorIs(t, b.Questions.Accept(ctx, missing, missing, 0), repository.ErrQuestionNotFound)
+
+	question := createQuestion(t, b, "Question")
+	require.NoError(t, b.Questions.Delete(ctx, question.ID, 0))
+	assert.ErrorIs(t, b.Questions.Delete(ctx, question.ID, 0), repository.ErrQuestionNotFound)
+	_, err = b.Questions.GetByID(ctx, question.ID)
+	assert.ErrorIs(t, err, repository.ErrQuestionNotFound)
+}
+
+func testAnswerRoundTrip(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	first := createAnswer(t, b, question.ID, "First answer")
+	second := createAnswer(t, b, question.ID, "Second answer")
+	assert.NotZero(t, first.ID)
+	assert.Greater(t, second.ID, first.ID)
+	assert.WithinDuration(t, time.Now(), first.CreatedAt, time.Minute)
+
+	got, err := b.Answers.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, got.ID)
+	assert.Equal(t, question.ID, got.QuestionID)
+	assert.Equal(t, userID, got.UserID)
+	assert.Equal(t, "First answer", got.Text)
+
+	withAnswers, err := b.Questions.GetByID(ctx, question.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{first.ID, second.ID}, answerIDs(withAnswers.Answers))
+}
+
+func testAnswerNotFound(t *testing.T, b Backend) {
+	ctx := context.Background()
+	const missing = 999999
+
+	_, err := b.Answers.GetByID(ctx, missing)
+	assert.ErrorIs(t, err, repository.ErrAnswerNotFound)
+	assert.ErrorIs(t, b.Answers.Delete(ctx, missing, 0), repository.ErrAnswerNotFound)
+	assert.ErrorIs(t, b.Answers.Restore(ctx, missing, 0), repository.ErrAnswerNotFound)
+	_, err = b.Answers.Update(ctx, missing, "text", userID, 0)
+	assert.ErrorIs(t, err, repository.ErrAnswerNotFound)
+	_, err = b.Answers.GetRevisions(ctx, missing)
+	assert.ErrorIs(t, err, repository.ErrAnswerNotFound)
+	_, err = b.Answers.Unvote(ctx, missing, userID, 0)
+	assert.ErrorIs(t, err, repository.ErrAnswerNotFound)
+
+	question := createQuestion(t, b, "Question")
+	answer := createAnswer(t, b, question.ID, "Answer")
+	require.NoError(t, b.Answers.Delete(ctx, answer.ID, 0))
+	assert.ErrorIs(t, b.Answers.Delete(ctx, answer.ID, 0), repository.ErrAnswerNotFound)
+	_, err = b.Answers.GetByID(ctx, answer.ID)
+	assert.ErrorIs(t, err, repository.ErrAnswerNotFound)
+}
+
+func testDeleteQuestionCascades(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	answer := createAnswer(t, b, question.ID, "Answer")
+	other := createQuestion(t, b, "Other question")
+	otherAnswer := createAnswer(t, b, other.ID, "Other answer")
+
+	require.NoError(t, b.Questions.Delete(ctx, question.ID, 0))
+
+	_, err := b.Answers.GetByID(ctx, answer.ID)
+	assert.ErrorIs(t, err, repository.ErrAnswerNotFound)
+	_, err = b.Answers.GetByID(ctx, otherAnswer.ID)
+	assert.NoError(t, err)
+}
+
+func testRestoreQuestion(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	deletedEarlier := createAnswer(t, b, question.ID, "Deleted earlier")
+	deletedWithQuestion := createAnswer(t, b, question.ID, "Deleted with the question")
+
+	require.NoError(t, b.Answers.Delete(ctx, deletedEarlier.ID, 0))
+	// Let the timestamps of the two deletions differ even on coarse clocks.
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, b.Questions.Delete(ctx, question.ID, 0))
+	require.NoError(t, b.Questions.Restore(ctx, question.ID, 0))
+	require.NoError(t, b.Questions.Restore(ctx, question.ID, 0), "restoring a question which is not deleted is a no-op")
+
+	got, err := b.Questions.GetByID(ctx, question.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{deletedWithQuestion.ID}, answerIDs(got.Answers))
+
+	require.NoError(t, b.Answers.Restore(ctx, deletedEarlier.ID, 0))
+	_, err = b.Answers.GetByID(ctx, deletedEarlier.ID)
+	assert.NoError(t, err)
+}
+
+func testRestoreAnswerOfDeletedQuestion(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	answer := createAnswer(t, b, question.ID, "Answer")
+	require.NoError(t, b.Questions.Delete(ctx, question.ID, 0))
+
+	assert.ErrorIs(t, b.Answers.Restore(ctx, answer.ID, 0), repository.ErrQuestionNotFound)
+}
+
+func testPurge(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	purged := createQuestion(t, b, "Purged question")
+	purgedAnswer := createAnswer(t, b, purged.ID, "Answer of the purged question")
+	kept := createQuestion(t, b, "Kept question")
+	deletedAnswer := createAnswer(t, b, kept.ID, "Purged answer")
+	keptAnswer := createAnswer(t, b, kept.ID, "Kept answer")
+
+	require.NoError(t, b.Questions.Delete(ctx, purged.ID, 0))
+	require.NoError(t, b.Answers.Delete(ctx, deletedAnswer.ID, 0))
+
+	n, err := b.Questions.Purge(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, n, "records deleted after the cutoff are kept")
+
+	n, err = b.Answers.Purge(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	n, err = b.Questions.Purge(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	assert.ErrorIs(t, b.Questions.Restore(ctx, purged.ID, 0), repository.ErrQuestionNotFound)
+	assert.ErrorIs(t, b.Answers.Restore(ctx, purgedAnswer.ID, 0), repository.ErrAnswerNotFound)
+	assert.ErrorIs(t, b.Answers.Restore(ctx, deletedAnswer.ID, 0), repository.ErrAnswerNotFound)
+	_, err = b.Answers.GetByID(ctx, keptAnswer.ID)
+	assert.NoError(t, err)
+}
+
+func testAnswersOrderedByScore(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	first := createAnswer(t, b, question.ID, "First")
+	second := createAnswer(t, b, question.ID, "Second")
+	third := createAnswer(t, b, question.ID, "Third")
+
+	_, err := b.Answers.Vote(ctx, third.ID, userID, 1, 0)
+	require.NoError(t, err)
+	_, err = b.Answers.Vote(ctx, first.ID, userID, -1, 0)
+	require.NoError(t, err)
+
+	got, err := b.Questions.GetByID(ctx, question.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{third.ID, second.ID, first.ID}, answerIDs(got.Answers))
+}
+
+func testGetAllOrderingAndCursor(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	var created []*model.Question
+	for _, text := range []string{"Q1", "Q2", "Q3", "Q4", "Q5"} {
+		created = append(created, createQuestion(t, b, text))
+	}
+	require.NoError(t, b.Questions.Delete(ctx, created[1].ID, 0))
+
+	page, err := b.Questions.GetAll(ctx, repository.QuestionQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Q1", "Q3"}, questionTexts(page))
+	assert.Empty(t, page[0].Answers)
+
+	last := page[len(page)-1]
+	page, err = b.Questions.GetAll(ctx, repository.QuestionQuery{
+		After: &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID},
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Q4", "Q5"}, questionTexts(page))
+}
+
+func testGetAllFiltersByTags(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	createQuestion(t, b, "Go", "go")
+	createQuestion(t, b, "Go and Postgres", "go", "postgres")
+	createQuestion(t, b, "Postgres", "postgres")
+	createQuestion(t, b, "Untagged")
+	deleted := createQuestion(t, b, "Deleted", "go")
+	require.NoError(t, b.Questions.Delete(ctx, deleted.ID, 0))
+
+	all, err := b.Questions.GetAll(ctx, repository.QuestionQuery{Limit: 10, Tags: []string{"go", "postgres"}, MatchAllTags: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Go and Postgres"}, questionTexts(all))
+
+	anyOf, err := b.Questions.GetAll(ctx, repository.QuestionQuery{Limit: 10, Tags: []string{"go", "postgres"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Go", "Go and Postgres", "Postgres"}, questionTexts(anyOf))
+	assert.Equal(t, []model.Tag{{Name: "go"}, {Name: "postgres"}}, namesOnly(anyOf[1].Tags))
+
+	tags, err := b.Questions.GetTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []model.TagCount{{Name: "go", Count: 2}, {Name: "postgres", Count: 2}}, tags)
+}
+
+func testVotes(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+
+	score, err := b.Questions.Vote(ctx, question.ID, userID, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, score)
+
+	score, err = b.Questions.Vote(ctx, question.ID, userID, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, score, "repeating a vote does not change the score")
+
+	score, err = b.Questions.Vote(ctx, question.ID, otherUserID, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, score)
+
+	score, err = b.Questions.Vote(ctx, question.ID, userID, -1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, score)
+
+	score, err = b.Questions.Unvote(ctx, question.ID, otherUserID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, -1, score)
+
+	score, err = b.Questions.Unvote(ctx, question.ID, otherUserID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, -1, score, "withdrawing a missing vote is a no-op")
+
+	got, err := b.Questions.GetByID(ctx, question.ID)
+	require.NoError(t, err)
+	assert.Equal(t, -1, got.Score)
+}
+
+func testSwitchVote(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	answer := createAnswer(t, b, question.ID, "Answer")
+
+	for _, value := range []int{1, -1, 1} {
+		score, err := b.Questions.Vote(ctx, question.ID, userID, value, 0)
+		require.NoError(t, err)
+		assert.Equal(t, value, score, "switching a question vote replaces the previous one")
+
+		score, err = b.Answers.Vote(ctx, answer.ID, userID, value, 0)
+		require.NoError(t, err)
+		assert.Equal(t, value, score, "switching an answer vote replaces the previous one")
+	}
+
+	got, err := b.Questions.GetByID(ctx, question.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Score)
+	require.Len(t, got.Answers, 1)
+	assert.Equal(t, 1, got.Answers[0].Score)
+}
+
+func testAccept(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	answer := createAnswer(t, b, question.ID, "Answer")
+	other := createQuestion(t, b, "Other question")
+	otherAnswer := createAnswer(t, b, other.ID, "Other answer")
+
+	assert.ErrorIs(t, b.Questions.Accept(ctx, question.ID, otherAnswer.ID, 0), repository.ErrAnswerNotFound)
+	require.NoError(t, b.Questions.Accept(ctx, question.ID, answer.ID, 0))
+
+	got, err := b.Questions.GetByID(ctx, question.ID)
+	require.NoError(t, err)
+	if assert.NotNil(t, got.AcceptedAnswerID) {
+		assert.Equal(t, answer.ID, *got.AcceptedAnswerID)
+	}
+}
+
+func testDeleteAcceptedAnswer(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	answer := createAnswer(t, b, question.ID, "Answer")
+	require.NoError(t, b.Questions.Accept(ctx, question.ID, answer.ID, 0))
+	require.NoError(t, b.Answers.Delete(ctx, answer.ID, 0))
+
+	got, err := b.Questions.GetByID(ctx, question.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.AcceptedAnswerID)
+}
+
+func testUpdateRecordsRevisions(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Original question")
+	updated, err := b.Questions.Update(ctx, question.ID, "Edited question", otherUserID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Edited question", updated.Text)
+
+	_, err = b.Questions.Update(ctx, question.ID, "Edited question", otherUserID, 0)
+	require.NoError(t, err)
+	_, err = b.Questions.Update(ctx, question.ID, "Edited twice", userID, 0)
+	require.NoError(t, err)
+
+	revisions, err := b.Questions.GetRevisions(ctx, question.ID)
+	require.NoError(t, err)
+	if assert.Len(t, revisions, 2, "an update which does not change the text is not recorded") {
+		assert.Equal(t, "Original question", revisions[0].Text)
+		assert.Equal(t, otherUserID, revisions[0].EditorID)
+		assert.Equal(t, "Edited question", revisions[1].Text)
+		assert.Equal(t, userID, revisions[1].EditorID)
+	}
+
+	answer := createAnswer(t, b, question.ID, "Original answer")
+	_, err = b.Answers.Update(ctx, answer.ID, "Edited answer", userID, 0)
+	require.NoError(t, err)
+
+	answerRevisions, err := b.Answers.GetRevisions(ctx, answer.ID)
+	require.NoError(t, err)
+	if assert.Len(t, answerRevisions, 1) {
+		assert.Equal(t, "Original answer", answerRevisions[0].Text)
+	}
+}
+
+func testUpdateLoadsAssociations(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Original question", "go", "sql")
+	first := createAnswer(t, b, question.ID, "First answer")
+	second := createAnswer(t, b, question.ID, "Second answer")
+
+	updated, err := b.Questions.Update(ctx, question.ID, "Edited question", userID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Tag{{Name: "go"}, {Name: "sql"}}, namesOnly(updated.Tags))
+	assert.Equal(t, []uint{first.ID, second.ID}, answerIDs(updated.Answers))
+
+	unchanged, err := b.Questions.Update(ctx, question.ID, "Edited question", userID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Tag{{Name: "go"}, {Name: "sql"}}, namesOnly(unchanged.Tags))
+	assert.Equal(t, []uint{first.ID, second.ID}, answerIDs(unchanged.Answers))
+}
+
+func questionVersion(t *testing.T, b Backend, id uint) int {
+	question, err := b.Questions.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	return question.Version
+}
+
+func answerVersion(t *testing.T, b Backend, id uint) int {
+	answer, err := b.Answers.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	return answer.Version
+}
+
+func testQuestionVersions(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	assert.Equal(t, 1, question.Version)
+	assert.Equal(t, 1, questionVersion(t, b, question.ID))
+
+	updated, err := b.Questions.Update(ctx, question.ID, "Edited question", userID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+	_, err = b.Questions.Update(ctx, question.ID, "Edited question", userID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, questionVersion(t, b, question.ID), "an update which does not change the text keeps the version")
+
+	_, err = b.Questions.Vote(ctx, question.ID, userID, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, questionVersion(t, b, question.ID))
+	_, err = b.Questions.Vote(ctx, question.ID, userID, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, questionVersion(t, b, question.ID), "repeating a vote keeps the version")
+
+	answer := createAnswer(t, b, question.ID, "Answer")
+	assert.Equal(t, 1, answer.Version)
+	assert.Equal(t, 4, questionVersion(t, b, question.ID), "a new answer changes the question")
+
+	require.NoError(t, b.Questions.Accept(ctx, question.ID, answer.ID, 0))
+	assert.Equal(t, 5, questionVersion(t, b, question.ID))
+	require.NoError(t, b.Questions.Accept(ctx, question.ID, answer.ID, 0))
+	assert.Equal(t, 5, questionVersion(t, b, question.ID), "accepting the accepted answer keeps the version")
+
+	require.NoError(t, b.Questions.Delete(ctx, question.ID, 5))
+	require.NoError(t, b.Questions.Restore(ctx, question.ID, 5), "a deleted question keeps its version")
+	assert.Equal(t, 5, questionVersion(t, b, question.ID), "a restored question is the same as before deletion")
+}
+
+func testAnswerVersions(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	answer := createAnswer(t, b, question.ID, "Answer")
+	version := questionVersion(t, b, question.ID)
+
+	updated, err := b.Answers.Update(ctx, answer.ID, "Edited answer", userID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+	assert.Equal(t, version+1, questionVersion(t, b, question.ID))
+
+	_, err = b.Answers.Vote(ctx, answer.ID, userID, -1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, answerVersion(t, b, answer.ID))
+	assert.Equal(t, version+2, questionVersion(t, b, question.ID))
+
+	require.NoError(t, b.Answers.Delete(ctx, answer.ID, 3))
+	assert.Equal(t, version+3, questionVersion(t, b, question.ID))
+	require.NoError(t, b.Answers.Restore(ctx, answer.ID, 3), "a deleted answer keeps its version")
+	assert.Equal(t, 3, answerVersion(t, b, answer.ID))
+	assert.Equal(t, version+4, questionVersion(t, b, question.ID))
+}
+
+func testVersionMismatch(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "Question")
+	answer := createAnswer(t, b, question.ID, "Answer")
+	stale := question.Version
+
+	assert.ErrorIs(t, b.Questions.Delete(ctx, question.ID, stale), repository.ErrVersionMismatch)
+	_, err := b.Questions.Update(ctx, question.ID, "Edited question", userID, stale)
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+	assert.ErrorIs(t, b.Questions.Accept(ctx, question.ID, answer.ID, stale), repository.ErrVersionMismatch)
+	assert.ErrorIs(t, b.Questions.Restore(ctx, question.ID, stale), repository.ErrVersionMismatch)
+	_, err = b.Questions.Vote(ctx, question.ID, userID, 1, stale)
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+	_, err = b.Questions.Unvote(ctx, question.ID, userID, stale)
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+	_, err = b.Answers.Update(ctx, answer.ID, "Edited answer", userID, 2)
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+	assert.ErrorIs(t, b.Answers.Delete(ctx, answer.ID, 2), repository.ErrVersionMismatch)
+	assert.ErrorIs(t, b.Answers.Restore(ctx, answer.ID, 2), repository.ErrVersionMismatch)
+	_, err = b.Answers.Vote(ctx, answer.ID, userID, 1, 2)
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+	_, err = b.Answers.Unvote(ctx, answer.ID, userID, 2)
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+	got, err := b.Questions.GetByID(ctx, question.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Question", got.Text, "a mismatched update changes nothing")
+	assert.Nil(t, got.AcceptedAnswerID)
+	assert.Zero(t, got.Score, "a mismatched vote changes nothing")
+	if assert.Len(t, got.Answers, 1) {
+		assert.Equal(t, "Answer", got.Answers[0].Text)
+		assert.Zero(t, got.Answers[0].Score)
+	}
+
+	assert.ErrorIs(t, b.Questions.Delete(ctx, 999999, 1), repository.ErrQuestionNotFound, "a missing question is not a mismatch")
+}
+
+func testIdempotencyKeys(t *testing.T, b Backend) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+
+	record := &model.IdempotencyRecord{UserID: userID, Key: "key", RequestHash: "hash", ExpiresAt: expiresAt}
+	created, err := b.Idempotency.Create(ctx, record)
+	require.NoError(t, err)
+	assert.Zero(t, created.StatusCode)
+
+	_, err = b.Idempotency.Create(ctx, &model.IdempotencyRecord{UserID: otherUserID, Key: "key", RequestHash: "other", ExpiresAt: expiresAt})
+	require.NoError(t, err, "keys are scoped to users")
+
+	existing, err := b.Idempotency.Create(ctx, &model.IdempotencyRecord{UserID: userID, Key: "key", RequestHash: "other", ExpiresAt: expiresAt})
+	require.ErrorIs(t, err, repository.ErrIdempotencyKeyExists)
+	assert.Equal(t, "hash", existing.RequestHash)
+	assert.Zero(t, existing.StatusCode)
+
+	body := []byte(`{"id":1}`)
+	headers := map[string]string{"Content-Type": "application/json", "ETag": `"1"`}
+	require.NoError(t, b.Idempotency.Complete(ctx, userID, "key", 201, headers, body, time.Now().Add(time.Hour)))
+	existing, err = b.Idempotency.Create(ctx, &model.IdempotencyRecord{UserID: userID, Key: "key", RequestHash: "hash", ExpiresAt: expiresAt})
+	require.ErrorIs(t, err, repository.ErrIdempotencyKeyExists)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, headers, existing.Headers)
+	assert.Equal(t, body, existing.Body)
+
+	assert.ErrorIs(t, b.Idempotency.Complete(ctx, userID, "missing", 201, headers, body, expiresAt), repository.ErrIdempotencyKeyNotFound)
+
+	require.NoError(t, b.Idempotency.Delete(ctx, userID, "key"))
+	require.NoError(t, b.Idempotency.Delete(ctx, userID, "key"))
+	_, err = b.Idempotency.Create(ctx, &model.IdempotencyRecord{UserID: userID, Key: "key", RequestHash: "other", ExpiresAt: expiresAt})
+	assert.NoError(t, err, "a deleted key can be used again")
+}
+
+func testIdempotencyExpiration(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	_, err := b.Idempotency.Create(ctx, &model.IdempotencyRecord{UserID: userID, Key: "expired", RequestHash: "hash", ExpiresAt: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	_, err = b.Idempotency.Create(ctx, &model.IdempotencyRecord{UserID: userID, Key: "kept", RequestHash: "hash", ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	replaced, err := b.Idempotency.Create(ctx, &model.IdempotencyRecord{UserID: userID, Key: "expired", RequestHash: "new", ExpiresAt: time.Now().Add(-time.Minute)})
+	require.NoError(t, err, "an expired record is replaced")
+	assert.Equal(t, "new", replaced.RequestHash)
+
+	n, err := b.Idempotency.Purge(ctx, time.Now())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	_, err = b.Idempotency.Create(ctx, &model.IdempotencyRecord{UserID: userID, Key: "kept", RequestHash: "hash", ExpiresAt: time.Now().Add(time.Hour)})
+	assert.ErrorIs(t, err, repository.ErrIdempotencyKeyExists)
+}
+
+func testRateLimitBuckets(t *testing.T, b Backend) {
+	ctx := context.Background()
+	limit := model.RateLimit{Requests: 1, Period: time.Second, Burst: 2}
+	start := time.Now().Truncate(time.Second)
+
+	for i, expected := range []struct {
+		taken  bool
+		tokens float64
+	}{{true, 1}, {true, 0}, {false, 0}} {
+		bucket, taken, err := b.RateLimits.Take(ctx, "client", limit, start)
+		require.NoError(t, err)
+		assert.Equal(t, expected.taken, taken, "request %d", i)
+		assert.InDelta(t, expected.tokens, bucket.Tokens, 0.001, "request %d", i)
+	}
+
+	_, taken, err := b.RateLimits.Take(ctx, "other client", limit, start)
+	require.NoError(t, err)
+	assert.True(t, taken, "buckets are separate")
+
+	bucket, taken, err := b.RateLimits.Take(ctx, "client", limit, start.Add(1500*time.Millisecond))
+	require.NoError(t, err)
+	assert.True(t, taken, "a token is replenished every second")
+	assert.InDelta(t, 0.5, bucket.Tokens, 0.001)
+	assert.True(t, bucket.ExpiresAt.Equal(start.Add(3*time.Second)), "ExpiresAt %v", bucket.ExpiresAt)
+
+	bucket, taken, err = b.RateLimits.Take(ctx, "client", limit, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, taken)
+	assert.InDelta(t, 1, bucket.Tokens, 0.001, "the bucket holds at most burst tokens")
+
+	n, err := b.RateLimits.Purge(ctx, start.Add(3*time.Second))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n, "only the bucket of the other client is full by then")
+}
+
+func testUserRoles(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	moderator, err := b.Users.GetByID(ctx, moderatorID)
+	require.NoError(t, err)
+	assert.Equal(t, moderatorID, moderator.ID)
+	assert.Equal(t, model.RoleModerator, moderator.Role)
+
+	admin, err := b.Users.GetByID(ctx, adminID)
+	require.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, admin.Role)
+
+	_, err = b.Users.GetByID(ctx, userID)
+	assert.ErrorIs(t, err, repository.ErrUserNotFound, "a user without a record is not found")
+}
+
+func testSearch(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	question := createQuestion(t, b, "How to sort a map in Go?")
+	keys := createAnswer(t, b, question.ID, "Sort the keys")
+	createAnswer(t, b, question.ID, "Iterate in any order")
+
+	hits, err := b.Search.Search(ctx, "KEYS", 10)
+	require.NoError(t, err)
+	if assert.Len(t, hits, 1, "words are matched case-insensitively") {
+		assert.Equal(t, model.SearchHitAnswer, hits[0].Type)
+		assert.Equal(t, keys.ID, hits[0].ID)
+		assert.Equal(t, question.ID, hits[0].QuestionID)
+		assert.Equal(t, "Sort the <mark>keys</mark>", hits[0].Snippet)
+	}
+
+	hits, err = b.Search.Search(ctx, "sort map", 10)
+	require.NoError(t, err)
+	if assert.Len(t, hits, 1, "texts must contain every word of the query") {
+		assert.Equal(t, model.SearchHitQuestion, hits[0].Type)
+		assert.Equal(t, question.ID, hits[0].ID)
+	}
+
+	hits, err = b.Search.Search(ctx, "sort", 1)
+	require.NoError(t, err)
+	assert.Len(t, hits, 1, "the number of hits is limited")
+
+	require.NoError(t, b.Answers.Delete(ctx, keys.ID, 0))
+	hits, err = b.Search.Search(ctx, "keys", 10)
+	require.NoError(t, err)
+	assert.Empty(t, hits, "deleted answers are not found")
+}
+
+func testSearchEscapesSnippets(t *testing.T, b Backend) {
+	question := createQuestion(t, b, "How to print HTML?")
+	createAnswer(t, b, question.ID, "<script>alert('xss')</script> & more")
+
+	hits, err := b.Search.Search(context.Background(), "more", 10)
+	require.NoError(t, err)
+	if assert.Len(t, hits, 1) {
+		assert.Contains(t, hits[0].Snippet, "<mark>more</mark>")
+		assert.Contains(t, hits[0].Snippet, "&lt;script&gt;")
+		assert.NotContains(t, hits[0].Snippet, "<script>", "user text is escaped")
+	}
+}
@@ -0,0 +1,77 @@
+package repositorytest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/config"
+	"github.com/ppb03/qna-api/migrations"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// OpenSQLite creates a migrated SQLite database in a temporary directory.
+func OpenSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(config.SQLiteDSN(filepath.Join(t.TempDir(), "qna.db"))), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+		Logger:  logger.Discard,
+	})
+	require.NoError(t, err)
+	Migrate(t, db, config.DriverSQLite)
+	return db
+}
+
+// OpenPostgres creates a migrated schema in the PostgreSQL database at dsn, which is dropped when the test ends,
+// so the database may be shared with other data.
+func OpenPostgres(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+
+	var suffix [8]byte
+	rand.Read(suffix[:])
+	schema := "test_" + hex.EncodeToString(suffix[:])
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC().Truncate(time.Microsecond) },
+		Logger:  logger.Discard,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	Migrate(t, db, config.DriverPostgres)
+	return db
+}
+
+// Migrate applies the embedded migrations of driver to db.
+func Migrate(t *testing.T, db *gorm.DB, driver string) {
+	t.Helper()
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	provider, err := migrations.NewProvider(sqlDB, driver)
+	require.NoError(t, err)
+	_, err = provider.Up(context.Background())
+	require.NoError(t, err)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type sqliteSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteSearchRepository creates SearchRepository instance which searches an SQLite database.
+// SQLite has no counterpart of the PostgreSQL full-text search, so texts are prefiltered with LIKE
+// and ranked the same way as by the in-memory implementation. It is meant for development rather than large data sets.
+func NewSQLiteSearchRepository(db *gorm.DB) SearchRepository {
+	return &sqliteSearchRepository{db: db}
+}
+
+func (r *sqliteSearchRepository) Search(ctx context.Context, query string, limit int) ([]model.SearchHit, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var questions []model.Question
+	if err := containingTerms(r.db.WithContext(ctx), terms).Find(&questions).Error; err != nil {
+		return nil, err
+	}
+	var answers []model.Answer
+	if err := containingTerms(r.db.WithContext(ctx), terms).Find(&answers).Error; err != nil {
+		return nil, err
+	}
+
+	var hits []model.SearchHit
+	for _, question := range questions {
+		if rank, snippet, ok := matchTerms(question.Text, terms); ok {
+			hits = append(hits, model.SearchHit{
+				Type: model.SearchHitQuestion, ID: question.ID, QuestionID: question.ID, Rank: rank, Snippet: snippet,
+			})
+		}
+	}
+	for _, answer := range answers {
+		if rank, snippet, ok := matchTerms(answer.Text, terms); ok {
+			hits = append(hits, model.SearchHit{
+				Type: model.SearchHitAnswer, ID: answer.ID, QuestionID: answer.QuestionID, Rank: rank, Snippet: snippet,
+			})
+		}
+	}
+
+	return topHits(hits, limit), nil
+}
+
+// containingTerms restricts db to rows whose text contains every term. LIKE is case-insensitive for ASCII letters only.
+func containingTerms(db *gorm.DB, terms []string) *gorm.DB {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	for _, term := range terms {
+		db = db.Where(`text LIKE ? ESCAPE '\'`, "%"+escaper.Replace(term)+"%")
+	}
+	return db
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+var tracer = otel.Tracer("github.com/ppb03/qna-api/internal/repository")
+
+// spanKey keeps the span of a query in the gorm statement between the before and after callbacks.
+const spanKey = "qna:span"
+
+type tracingPlugin struct{}
+
+// NewTracingPlugin creates gorm plugin which records queries as OpenTelemetry spans, children of the span
+// carried by the context passed to WithContext. Queries outside of a trace, such as the startup schema checks,
+// are not recorded, nor are query variables since they carry user texts.
+func NewTracingPlugin() gorm.Plugin {
+	return tracingPlugin{}
+}
+
+func (tracingPlugin) Name() string {
+	return "qna:tracing"
+}
+
+func (tracingPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("qna:trace_before_create", startSpan("INSERT")),
+		callback.Create().After("gorm:create").Register("qna:trace_after_create", endSpan),
+		callback.Query().Before("gorm:query").Register("qna:trace_before_query", startSpan("SELECT")),
+		callback.Query().After("gorm:query").Register("qna:trace_after_query", endSpan),
+		callback.Update().Before("gorm:update").Register("qna:trace_before_update", startSpan("UPDATE")),
+		callback.Update().After("gorm:update").Register("qna:trace_after_update", endSpan),
+		callback.Delete().Before("gorm:delete").Register("qna:trace_before_delete", startSpan("DELETE")),
+		callback.Delete().After("gorm:delete").Register("qna:trace_after_delete", endSpan),
+		callback.Row().Before("gorm:row").Register("qna:trace_before_row", startSpan("ROW")),
+		callback.Row().After("gorm:row").Register("qna:trace_after_row", endSpan),
+		callback.Raw().Before("gorm:raw").Register("qna:trace_before_raw", startSpan("RAW")),
+		callback.Raw().After("gorm:raw").Register("qna:trace_after_raw", endSpan),
+	)
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !trace.SpanContextFromContext(db.Statement.Context).IsValid() {
+			return
+		}
+
+		name := operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		_, span := tracer.Start(db.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				dbSystem(db.Dialector.Name()),
+				semconv.DBOperationName(operation),
+				semconv.DBCollectionName(db.Statement.Table),
+			),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(semconv.DBQueryText(db.Statement.SQL.String()), attribute.Int64("db.response.affected_rows", db.RowsAffected))
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
+
+func dbSystem(dialector string) attribute.KeyValue {
+	switch dialector {
+	case "postgres":
+		return semconv.DBSystemNamePostgreSQL
+	case "sqlite":
+		return semconv.DBSystemNameSQLite
+	default:
+		return semconv.DBSystemNameKey.String(dialector)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/ppb03/qna-api/internal/metrics"
 	"github.com/ppb03/qna-api/internal/model"
 	"github.com/ppb03/qna-api/internal/repository"
 )
@@ -11,58 +12,191 @@ import (
 type answerService struct {
 	answerRepository   repository.AnswerRepository
 	questionRepository repository.QuestionRepository
+	authorizer         authorizer
 }
 
 // NewAnswerService creates AnswerService instance with standart implementation
-func NewAnswerService(answerRepository repository.AnswerRepository, questionRepository repository.QuestionRepository) AnswerService {
-	return &answerService{answerRepository: answerRepository, questionRepository: questionRepository}
+func NewAnswerService(answerRepository repository.AnswerRepository, questionRepository repository.QuestionRepository, userRepository repository.UserRepository) AnswerService {
+	return &answerService{
+		answerRepository:   answerRepository,
+		questionRepository: questionRepository,
+		authorizer:         authorizer{userRepository: userRepository},
+	}
 }
 
-func (as *answerService) Create(ctx context.Context, questionID uint, userID, text string) (*model.Answer, error) {
-	if text == "" {
-		return nil, ErrEmptyText
-	}
-	
-	if userID == "" {
-		return nil, ErrEmptyUserID
+func (as *answerService) Create(ctx context.Context, questionID uint, text string) (*model.Answer, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if !isValidUUID(userID) {
-		return nil, ErrInvalidUserID
+	if text == "" {
+		return nil, ErrEmptyText
 	}
 
-	_, err := as.questionRepository.GetByID(ctx, questionID)
+	_, err = as.questionRepository.GetByID(ctx, questionID)
 	if err != nil {
 		if errors.Is(err, repository.ErrQuestionNotFound) {
 			return nil, ErrQuestionNotExists
 		}
-		return nil, internalError(err, ErrRepositoryFailure)
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
 	}
 
 	answer, err := as.answerRepository.Create(ctx, &model.Answer{QuestionID: questionID, UserID: userID, Text: text})
 	if err != nil {
-		return nil, internalError(err, ErrRepositoryFailure)
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
 	}
+	metrics.AnswersCreated.Inc()
 	return answer, nil
 }
 
-func (as *answerService) Delete(ctx context.Context, id uint) error {
-	if err := as.answerRepository.Delete(ctx, id); err != nil {
+func (as *answerService) Delete(ctx context.Context, id uint, version int) error {
+	if _, err := currentUserID(ctx); err != nil {
+		return err
+	}
+
+	answer, err := as.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := as.authorizer.authorizeOwner(ctx, answer.UserID); err != nil {
+		return err
+	}
+
+	if err := as.answerRepository.Delete(ctx, id, version); err != nil {
 		if errors.Is(err, repository.ErrAnswerNotFound) {
 			return ErrAnswerNotExists
 		}
-		return internalError(err, ErrRepositoryFailure)
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return ErrPreconditionFailed
+		}
+		return internalError(ctx, err, ErrRepositoryFailure)
 	}
 	return nil
 }
 
+func (as *answerService) Restore(ctx context.Context, id uint, version int) (*model.Answer, error) {
+	// Deleted answers are hidden from reads, so their authorship cannot be checked and restoring is left to moderators.
+	if _, err := as.authorizer.authorizeModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := as.answerRepository.Restore(ctx, id, version); err != nil {
+		if errors.Is(err, repository.ErrAnswerNotFound) {
+			return nil, ErrAnswerNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, ErrQuestionNotExists
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return as.GetByID(ctx, id)
+}
+
 func (as *answerService) GetByID(ctx context.Context, id uint) (*model.Answer, error) {
 	answer, err := as.answerRepository.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrAnswerNotFound) {
 			return nil, ErrAnswerNotExists
 		}
-		return nil, internalError(err, ErrRepositoryFailure)
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return answer, nil
+}
+
+func (as *answerService) Vote(ctx context.Context, id uint, value, version int) (*model.VoteSummary, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if value != 1 && value != -1 {
+		return nil, ErrInvalidVote
+	}
+
+	score, err := as.answerRepository.Vote(ctx, id, userID, value, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrAnswerNotFound) {
+			return nil, ErrAnswerNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return &model.VoteSummary{Score: score, Vote: value}, nil
+}
+
+func (as *answerService) Unvote(ctx context.Context, id uint, version int) (*model.VoteSummary, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := as.answerRepository.Unvote(ctx, id, userID, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrAnswerNotFound) {
+			return nil, ErrAnswerNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return &model.VoteSummary{Score: score}, nil
+}
+
+func (as *answerService) Update(ctx context.Context, id uint, text string, version int) (*model.Answer, error) {
+	if _, err := currentUserID(ctx); err != nil {
+		return nil, err
+	}
+
+	if text == "" {
+		return nil, ErrEmptyText
+	}
+
+	answer, err := as.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	editorID, err := as.authorizer.authorizeOwner(ctx, answer.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err = as.answerRepository.Update(ctx, id, text, editorID, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrAnswerNotFound) {
+			return nil, ErrAnswerNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
 	}
 	return answer, nil
 }
+
+func (as *answerService) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	answer, err := as.answerRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAnswerNotFound) {
+			return nil, ErrAnswerNotExists
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+
+	revisions, err := as.answerRepository.GetRevisions(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAnswerNotFound) {
+			return nil, ErrAnswerNotExists
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return withDiffs(revisions, answer.Text), nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+)
+
+// authorizer decides whether the authenticated caller may modify a post based on authorship and role.
+type authorizer struct {
+	userRepository repository.UserRepository
+}
+
+// authorizeOwner returns the caller's ID if the caller is the author of a post owned by ownerID
+// or has a moderator or admin role.
+func (a authorizer) authorizeOwner(ctx context.Context, ownerID string) (string, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if ownerID != "" && userID == ownerID {
+		return userID, nil
+	}
+	return a.authorizeModerator(ctx)
+}
+
+// authorizeModerator returns the caller's ID if the caller has a moderator or admin role.
+func (a authorizer) authorizeModerator(ctx context.Context) (string, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := a.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return "", ErrForbidden
+		}
+		return "", internalError(ctx, err, ErrRepositoryFailure)
+	}
+
+	if user.Role != model.RoleModerator && user.Role != model.RoleAdmin {
+		return "", ErrForbidden
+	}
+	return userID, nil
+}
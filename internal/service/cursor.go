@@ -0,0 +1,43 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/repository"
+)
+
+var errMalformedCursor = errors.New("malformed cursor")
+
+// encodeCursor turns a repository cursor into an opaque string safe to pass in a query parameter.
+func encodeCursor(c repository.Cursor) string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + strconv.FormatUint(uint64(c.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a cursor previously produced by encodeCursor.
+func decodeCursor(s string) (*repository.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, errMalformedCursor
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	questionID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Cursor{CreatedAt: time.Unix(0, unixNano).UTC(), ID: uint(questionID)}, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/ppb03/qna-api/internal/model"
+)
+
+// maxDiffCells bounds the size of the LCS table built by diffWords.
+// Texts exceeding it are diffed as a whole deletion followed by a whole insertion.
+const maxDiffCells = 1 << 20
+
+// withDiffs fills the Diff of each revision with the changes between it and the next version of the text.
+// Revisions must be ordered from oldest to newest, current is the text of the latest version.
+func withDiffs(revisions []model.Revision, current string) []model.Revision {
+	if revisions == nil {
+		return []model.Revision{}
+	}
+
+	for i := range revisions {
+		next := current
+		if i+1 < len(revisions) {
+			next = revisions[i+1].Text
+		}
+		revisions[i].Diff = diffWords(revisions[i].Text, next)
+	}
+	return revisions
+}
+
+// diffWords computes a word-level diff between two texts using the longest common subsequence.
+func diffWords(from, to string) []model.DiffOp {
+	a, b := strings.Fields(from), strings.Fields(to)
+	n, m := len(a), len(b)
+
+	var ops []model.DiffOp
+	if (n+1)*(m+1) > maxDiffCells {
+		ops = appendDiffOp(ops, model.DiffDelete, a...)
+		return appendDiffOp(ops, model.DiffInsert, b...)
+	}
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = appendDiffOp(ops, model.DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = appendDiffOp(ops, model.DiffDelete, a[i])
+			i++
+		default:
+			ops = appendDiffOp(ops, model.DiffInsert, b[j])
+			j++
+		}
+	}
+	ops = appendDiffOp(ops, model.DiffDelete, a[i:]...)
+	return appendDiffOp(ops, model.DiffInsert, b[j:]...)
+}
+
+// appendDiffOp appends words to ops, merging them into the last operation when it is of the same kind.
+func appendDiffOp(ops []model.DiffOp, op string, words ...string) []model.DiffOp {
+	if len(words) == 0 {
+		return ops
+	}
+
+	text := strings.Join(words, " ")
+	if len(ops) > 0 && ops[len(ops)-1].Op == op {
+		ops[len(ops)-1].Text += " " + text
+		return ops
+	}
+	return append(ops, model.DiffOp{Op: op, Text: text})
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+)
+
+// ErrShuttingDown is reported by the readiness probe once the server has started to shut down.
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// ReadinessCheck is a named check of a dependency the API needs to serve requests.
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type healthService struct {
+	checks   []ReadinessCheck
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewHealthService creates HealthService instance which runs checks concurrently, each limited by timeout
+func NewHealthService(timeout time.Duration, checks ...ReadinessCheck) HealthService {
+	return &healthService{checks: checks, timeout: timeout}
+}
+
+func (hs *healthService) Ready(ctx context.Context) *model.HealthReport {
+	if hs.draining.Load() {
+		return &model.HealthReport{
+			Status: model.HealthUnavailable,
+			Checks: []model.HealthCheck{{Name: "shutdown", Status: model.HealthUnavailable, Error: ErrShuttingDown.Error()}},
+		}
+	}
+
+	report := &model.HealthReport{Status: model.HealthOK, Checks: make([]model.HealthCheck, len(hs.checks))}
+
+	var wg sync.WaitGroup
+	for i, check := range hs.checks {
+		wg.Go(func() {
+			report.Checks[i] = hs.run(ctx, check)
+		})
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		if check.Status != model.HealthOK {
+			report.Status = model.HealthUnavailable
+		}
+	}
+	return report
+}
+
+func (hs *healthService) run(ctx context.Context, check ReadinessCheck) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, hs.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+	result := model.HealthCheck{Name: check.Name, Status: model.HealthOK, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = model.HealthUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (hs *healthService) Drain() {
+	hs.draining.Store(true)
+}
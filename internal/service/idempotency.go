@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+)
+
+// idempotencyLockTimeout limits how long a key stays claimed by a request which neither completed nor released it,
+// e.g. because the server crashed. It is well above the write timeout of the server.
+const idempotencyLockTimeout = time.Minute
+
+// maxIdempotencyKeyLength matches the size of the key column.
+const maxIdempotencyKeyLength = 255
+
+type idempotencyService struct {
+	idempotencyRepository repository.IdempotencyRepository
+	ttl                   time.Duration
+}
+
+// NewIdempotencyService creates IdempotencyService instance which keeps responses for replay during ttl
+func NewIdempotencyService(idempotencyRepository repository.IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{idempotencyRepository: idempotencyRepository, ttl: ttl}
+}
+
+func (is *idempotencyService) Begin(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isValidIdempotencyKey(key) {
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	record := &model.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().Add(idempotencyLockTimeout),
+	}
+	existing, err := is.idempotencyRepository.Create(ctx, record)
+	switch {
+	case err == nil:
+		return nil, nil
+	case !errors.Is(err, repository.ErrIdempotencyKeyExists):
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	case existing.RequestHash != requestHash:
+		return nil, ErrIdempotencyKeyReused
+	case existing.StatusCode == 0:
+		return nil, ErrIdempotencyKeyInProgress
+	default:
+		return existing, nil
+	}
+}
+
+func (is *idempotencyService) Complete(ctx context.Context, key string, statusCode int, headers map[string]string, body []byte) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := is.idempotencyRepository.Complete(ctx, userID, key, statusCode, headers, body, time.Now().Add(is.ttl)); err != nil {
+		return internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return nil
+}
+
+func (is *idempotencyService) Release(ctx context.Context, key string) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := is.idempotencyRepository.Delete(ctx, userID, key); err != nil {
+		return internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return nil
+}
+
+// isValidIdempotencyKey reports whether key is short and made of printable ASCII characters.
+func isValidIdempotencyKey(key string) bool {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
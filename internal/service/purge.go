@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/logging"
+	"github.com/ppb03/qna-api/internal/repository"
+)
+
+type purgeService struct {
+	questionRepository    repository.QuestionRepository
+	answerRepository      repository.AnswerRepository
+	idempotencyRepository repository.IdempotencyRepository
+	rateLimitRepository   repository.RateLimitRepository
+	retention             time.Duration
+}
+
+// NewPurgeService creates PurgeService instance which purges rows soft-deleted longer than retention ago along with expired idempotency keys and rate limit buckets
+func NewPurgeService(questionRepository repository.QuestionRepository, answerRepository repository.AnswerRepository, idempotencyRepository repository.IdempotencyRepository, rateLimitRepository repository.RateLimitRepository, retention time.Duration) PurgeService {
+	return &purgeService{
+		questionRepository:    questionRepository,
+		answerRepository:      answerRepository,
+		idempotencyRepository: idempotencyRepository,
+		rateLimitRepository:   rateLimitRepository,
+		retention:             retention,
+	}
+}
+
+func (ps *purgeService) Purge(ctx context.Context) error {
+	before := time.Now().Add(-ps.retention)
+
+	// Questions go first so that their answers are removed by the cascade and not counted twice.
+	questions, err := ps.questionRepository.Purge(ctx, before)
+	if err != nil {
+		return internalError(ctx, err, ErrRepositoryFailure)
+	}
+
+	answers, err := ps.answerRepository.Purge(ctx, before)
+	if err != nil {
+		return internalError(ctx, err, ErrRepositoryFailure)
+	}
+
+	logging.FromContext(ctx).Info("purged soft-deleted records", "questions", questions, "answers", answers, "deleted_before", before)
+
+	keys, err := ps.idempotencyRepository.Purge(ctx, time.Now())
+	if err != nil {
+		return internalError(ctx, err, ErrRepositoryFailure)
+	}
+	logging.FromContext(ctx).Info("purged expired idempotency keys", "keys", keys)
+
+	buckets, err := ps.rateLimitRepository.Purge(ctx, time.Now())
+	if err != nil {
+		return internalError(ctx, err, ErrRepositoryFailure)
+	}
+	logging.FromContext(ctx).Info("purged full rate limit buckets", "buckets", buckets)
+	return nil
+}
@@ -4,57 +4,298 @@ import (
 	"context"
 	"errors"
 
+	"github.com/ppb03/qna-api/internal/metrics"
 	"github.com/ppb03/qna-api/internal/model"
 	"github.com/ppb03/qna-api/internal/repository"	
 )
 
 type questionService struct {
 	repository repository.QuestionRepository
+	authorizer authorizer
 }
 
 // NewQuestionService creates QuestionService instance with standart implementation
-func NewQuestionService(repository repository.QuestionRepository) QuestionService {
-	return &questionService{repository: repository}
+func NewQuestionService(repository repository.QuestionRepository, userRepository repository.UserRepository) QuestionService {
+	return &questionService{repository: repository, authorizer: authorizer{userRepository: userRepository}}
 }
 
-func (qs *questionService) Create(ctx context.Context, text string) (*model.Question, error){
+func (qs *questionService) Create(ctx context.Context, text string, tags []string) (*model.Question, error){
+	authorID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	if text == "" {
 		return nil, ErrEmptyText
 	}
 
-	question, err := qs.repository.Create(ctx, &model.Question{Text: text})
+	names, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	question := &model.Question{AuthorID: authorID, Text: text}
+	for _, name := range names {
+		question.Tags = append(question.Tags, model.Tag{Name: name})
+	}
+
+	question, err = qs.repository.Create(ctx, question)
 	if err != nil {
-		return nil, internalError(err, ErrRepositoryFailure)
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
 	}
+	metrics.QuestionsCreated.Inc()
 	return question, nil
 }
 
-func (qs *questionService) Delete(ctx context.Context, id uint) error {
-	if err := qs.repository.Delete(ctx, id); err != nil {
+func (qs *questionService) Delete(ctx context.Context, id uint, version int) error {
+	if _, err := currentUserID(ctx); err != nil {
+		return err
+	}
+
+	question, err := qs.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qs.authorizer.authorizeOwner(ctx, question.AuthorID); err != nil {
+		return err
+	}
+
+	if err := qs.repository.Delete(ctx, id, version); err != nil {
 		if errors.Is(err, repository.ErrQuestionNotFound) {
 			return ErrQuestionNotExists
 		}
-		return internalError(err, ErrRepositoryFailure)
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return ErrPreconditionFailed
+		}
+		return internalError(ctx, err, ErrRepositoryFailure)
 	}
 	return nil
 }
 
+func (qs *questionService) Restore(ctx context.Context, id uint, version int) (*model.Question, error) {
+	// Deleted questions are hidden from reads, so their authorship cannot be checked and restoring is left to moderators.
+	if _, err := qs.authorizer.authorizeModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := qs.repository.Restore(ctx, id, version); err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, ErrQuestionNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return qs.GetByID(ctx, id)
+}
+
 func (qs *questionService) GetByID(ctx context.Context, id uint) (*model.Question, error) {
 	question, err := qs.repository.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrQuestionNotFound) {
 			return nil, ErrQuestionNotExists
 		}
-		return nil, internalError(err, ErrRepositoryFailure)
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
 	} 
+	sortAcceptedFirst(question)
 	return question, nil
 }
 
-func (qs *questionService) GetAll(ctx context.Context) ([]model.Question, error) {
-	questions, err := qs.repository.GetAll(ctx)
+// sortAcceptedFirst moves the accepted answer, if any, to the front keeping the order of the others.
+func sortAcceptedFirst(question *model.Question) {
+	if question.AcceptedAnswerID == nil {
+		return
+	}
+	for i, answer := range question.Answers {
+		if answer.ID == *question.AcceptedAnswerID {
+			copy(question.Answers[1:i+1], question.Answers[:i])
+			question.Answers[0] = answer
+			return
+		}
+	}
+}
+
+func (qs *questionService) Accept(ctx context.Context, id, answerID uint, version int) (*model.Question, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := qs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if question.AuthorID == "" || question.AuthorID != userID {
+		return nil, ErrNotQuestionAuthor
+	}
+
+	if err := qs.repository.Accept(ctx, id, answerID, version); err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, ErrQuestionNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		if errors.Is(err, repository.ErrAnswerNotFound) {
+			return nil, ErrAnswerNotInQuestion
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return qs.GetByID(ctx, id)
+}
+
+func (qs *questionService) Vote(ctx context.Context, id uint, value, version int) (*model.VoteSummary, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if value != 1 && value != -1 {
+		return nil, ErrInvalidVote
+	}
+
+	score, err := qs.repository.Vote(ctx, id, userID, value, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, ErrQuestionNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return &model.VoteSummary{Score: score, Vote: value}, nil
+}
+
+func (qs *questionService) Unvote(ctx context.Context, id uint, version int) (*model.VoteSummary, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := qs.repository.Unvote(ctx, id, userID, version)
 	if err != nil {
-		return nil, internalError(err, ErrRepositoryFailure)
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, ErrQuestionNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return &model.VoteSummary{Score: score}, nil
+}
+
+func (qs *questionService) Update(ctx context.Context, id uint, text string, version int) (*model.Question, error) {
+	if _, err := currentUserID(ctx); err != nil {
+		return nil, err
+	}
+
+	if text == "" {
+		return nil, ErrEmptyText
+	}
+
+	question, err := qs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	editorID, err := qs.authorizer.authorizeOwner(ctx, question.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err = qs.repository.Update(ctx, id, text, editorID, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, ErrQuestionNotExists
+		}
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, ErrPreconditionFailed
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	sortAcceptedFirst(question)
+	return question, nil
+}
+
+func (qs *questionService) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	question, err := qs.repository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, ErrQuestionNotExists
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+
+	revisions, err := qs.repository.GetRevisions(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, ErrQuestionNotExists
+		}
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	return withDiffs(revisions, question.Text), nil
+}
+
+func (qs *questionService) GetAll(ctx context.Context, params QuestionListParams) (*model.QuestionPage, error) {
+	limit := params.Limit
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	if limit < 0 || limit > MaxPageSize {
+		return nil, ErrInvalidLimit
+	}
+
+	query := repository.QuestionQuery{Limit: limit + 1}
+	if params.Match != "" && params.Match != TagMatchAll && params.Match != TagMatchAny {
+		return nil, ErrInvalidTagMatch
+	}
+	if len(params.Tags) > 0 {
+		tags, err := normalizeTags(params.Tags)
+		if err != nil {
+			return nil, err
+		}
+		query.Tags = tags
+		query.MatchAllTags = params.Match != TagMatchAny
+	}
+
+	if params.Cursor != "" {
+		after, err := decodeCursor(params.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		query.After = after
+	}
+
+	questions, err := qs.repository.GetAll(ctx, query)
+	if err != nil {
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+
+	page := &model.QuestionPage{Questions: questions}
+	if len(questions) > limit {
+		page.Questions = questions[:limit]
+		last := page.Questions[limit-1]
+		page.NextCursor = encodeCursor(repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	if page.Questions == nil {
+		page.Questions = []model.Question{}
+	}
+	return page, nil
+}
+
+func (qs *questionService) GetTags(ctx context.Context) ([]model.TagCount, error) {
+	tags, err := qs.repository.GetTags(ctx)
+	if err != nil {
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+	if tags == nil {
+		tags = []model.TagCount{}
 	}
-	return questions, nil
+	return tags, nil
 }
 
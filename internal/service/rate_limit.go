@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+)
+
+type rateLimitService struct {
+	rateLimitRepository repository.RateLimitRepository
+}
+
+// NewRateLimitService creates RateLimitService instance which keeps token buckets via the given repository
+func NewRateLimitService(rateLimitRepository repository.RateLimitRepository) RateLimitService {
+	return &rateLimitService{rateLimitRepository: rateLimitRepository}
+}
+
+func (rs *rateLimitService) Allow(ctx context.Context, key string, limit model.RateLimit) (*model.RateLimitStatus, error) {
+	now := time.Now()
+	bucket, allowed, err := rs.rateLimitRepository.Take(ctx, key, limit, now)
+	if err != nil {
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+
+	status := &model.RateLimitStatus{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(bucket.Tokens),
+		Reset:     bucket.ExpiresAt.Sub(now),
+	}
+	if !allowed {
+		status.RetryAfter = time.Duration((1 - bucket.Tokens) * float64(limit.Interval()))
+	}
+	return status, nil
+}
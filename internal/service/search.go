@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ppb03/qna-api/internal/model"
+	"github.com/ppb03/qna-api/internal/repository"
+)
+
+type searchService struct {
+	repository repository.SearchRepository
+}
+
+// NewSearchService creates SearchService instance with standart implementation
+func NewSearchService(repository repository.SearchRepository) SearchService {
+	return &searchService{repository: repository}
+}
+
+func (ss *searchService) Search(ctx context.Context, query string, limit int) (*model.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptyQuery
+	}
+
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	if limit < 0 || limit > MaxPageSize {
+		return nil, ErrInvalidLimit
+	}
+
+	hits, err := ss.repository.Search(ctx, query, limit)
+	if err != nil {
+		return nil, internalError(ctx, err, ErrRepositoryFailure)
+	}
+
+	if hits == nil {
+		hits = []model.SearchHit{}
+	}
+	return &model.SearchResult{Hits: hits}, nil
+}
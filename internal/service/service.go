@@ -4,19 +4,35 @@ package service
 import (
 	"context"
 	"errors"
-	"log/slog"
 	"regexp"
 
+	"github.com/ppb03/qna-api/internal/auth"
+	"github.com/ppb03/qna-api/internal/logging"
 	"github.com/ppb03/qna-api/internal/model"
 )
 
 // Client-side errors
 var (
-	ErrEmptyText         = errors.New("text cannot be empty")
-	ErrEmptyUserID       = errors.New("user ID cannot be empty") // ! Не имеет смысла при имеющейся ErrInvalidUserID
-	ErrInvalidUserID     = errors.New("user ID must be a valid UUID")
-	ErrQuestionNotExists = errors.New("no question with such ID")
-	ErrAnswerNotExists   = errors.New("no answer with such ID")
+	ErrEmptyText           = errors.New("text cannot be empty")
+	ErrInvalidUserID       = errors.New("user ID must be a valid UUID")
+	ErrQuestionNotExists   = errors.New("no question with such ID")
+	ErrAnswerNotExists     = errors.New("no answer with such ID")
+	ErrInvalidLimit        = errors.New("limit must be between 1 and 100")
+	ErrInvalidCursor       = errors.New("invalid pagination cursor")
+	ErrEmptyQuery          = errors.New("search query cannot be empty")
+	ErrUnauthenticated     = errors.New("authentication required")
+	ErrInvalidVote         = errors.New("vote value must be 1 or -1")
+	ErrAnswerNotInQuestion = errors.New("answer does not belong to this question")
+	ErrInvalidTag          = errors.New("tags must be non-empty and at most 32 characters long")
+	ErrInvalidTagMatch     = errors.New("tag match must be either all or any")
+	ErrForbidden           = errors.New("only the author, a moderator or an admin can modify this post")
+	ErrNotQuestionAuthor   = errors.New("only the author of the question can accept an answer")
+	ErrPreconditionFailed  = errors.New("the resource has been modified since the given version")
+	ErrRateLimitExceeded   = errors.New("too many requests")
+
+	ErrInvalidIdempotencyKey    = errors.New("idempotency key must be 1 to 255 printable ASCII characters")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
 )
 
 // Internal errors
@@ -24,43 +40,180 @@ var (
 	ErrRepositoryFailure = errors.New("repository failure")
 )
 
+// Page size bounds for paginated listings.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// QuestionListParams describes a page of questions requested by a client.
+type QuestionListParams struct {
+	// Limit is the maximum number of questions on the page. Zero means DefaultPageSize.
+	Limit int
+
+	// Cursor is an opaque value taken from the NextCursor of a previous page. Empty means the first page.
+	Cursor string
+
+	// Tags restricts the page to questions labelled with the given tags. Empty means no restriction.
+	Tags []string
+
+	// Match is either TagMatchAll (the default when empty) or TagMatchAny.
+	Match string
+}
+
 // QuestionService defines the interface for operations related to questions on service layer.
 //
 // Standart implementation can be obtained via NewQuestionService() function.
+//
+// Methods taking a version fail with ErrPreconditionFailed unless it is zero or the current version of the question.
 type QuestionService interface {
-	// Create creates a new question and persists it in database via underlying repository
-	Create(ctx context.Context, text string) (*model.Question, error)
+	// Create creates a new question authored by the authenticated caller and persists it in database via underlying repository
+	// Tags are normalized before saving, see NormalizeTag.
+	Create(ctx context.Context, text string, tags []string) (*model.Question, error)
+
+	// Delete soft-deletes a question along with its answers based on its ID via underlying repository.
+	// Only the author of the question, a moderator or an admin can delete it.
+	Delete(ctx context.Context, id uint, version int) error
+
+	// Restore brings back a soft-deleted question along with its answers and returns it.
+	// Only a moderator or an admin can restore a question.
+	Restore(ctx context.Context, id uint, version int) (*model.Question, error)
 
-	// Delete removes a question from the database based on its ID via underlying repository.
-	Delete(ctx context.Context, id uint) error
+	// Update replaces the text of a question on behalf of the authenticated caller and keeps the previous text in its revision history.
+	// Only the author of the question, a moderator or an admin can edit it.
+	Update(ctx context.Context, id uint, text string, version int) (*model.Question, error)
 
-	// GetAll retrieves all questions from the database via underlying repository.
-	GetAll(ctx context.Context) ([]model.Question, error)
+	// GetRevisions retrieves the revision history of a question, each revision carrying a diff to the version that replaced it.
+	GetRevisions(ctx context.Context, id uint) ([]model.Revision, error)
+
+	// GetAll retrieves a page of questions ordered by creation time via underlying repository.
+	GetAll(ctx context.Context, params QuestionListParams) (*model.QuestionPage, error)
+
+	// GetTags retrieves tags used by questions along with their usage counts via underlying repository.
+	GetTags(ctx context.Context) ([]model.TagCount, error)
 
 	// GetByID retrieves a question from the database based on its ID along with its associated answers via underlying repository.
+	// The accepted answer goes first, the rest are sorted by score, highest first.
 	GetByID(ctx context.Context, id uint) (*model.Question, error)
+
+	// Vote casts the authenticated caller's up (1) or down (-1) vote for a question, replacing their previous vote.
+	Vote(ctx context.Context, id uint, value, version int) (*model.VoteSummary, error)
+
+	// Unvote withdraws the authenticated caller's vote for a question.
+	Unvote(ctx context.Context, id uint, version int) (*model.VoteSummary, error)
+
+	// Accept marks one of the question's answers as the solution. Only the author of the question can accept answers.
+	Accept(ctx context.Context, id, answerID uint, version int) (*model.Question, error)
 }
 
 // AnswerService defines the interface for operations related to answers on service layer.
 //
 // Standart implementation can be obtained via NewAnswerService() function.
+//
+// Methods taking a version fail with ErrPreconditionFailed unless it is zero or the current version of the answer.
 type AnswerService interface {
-	// Create creates a new answer and persists it to the database via underlying repository.
-	Create(ctx context.Context, questionID uint, userID, text string) (*model.Answer, error)
+	// Create creates a new answer on behalf of the authenticated caller and persists it to the database via underlying repository.
+	Create(ctx context.Context, questionID uint, text string) (*model.Answer, error)
+
+	// Delete soft-deletes an answer based on its ID via underlying repository.
+	// Only the author of the answer, a moderator or an admin can delete it.
+	Delete(ctx context.Context, id uint, version int) error
 
-	// Delete removes an answer from the database based on its ID via underlying repository.
-	Delete(ctx context.Context, id uint) error
+	// Restore brings back a soft-deleted answer and returns it. The question of the answer must not be deleted.
+	// Only a moderator or an admin can restore an answer.
+	Restore(ctx context.Context, id uint, version int) (*model.Answer, error)
 
 	// GetByID retrieves an answer from the database based on its ID via underlying repository.
 	GetByID(ctx context.Context, id uint) (*model.Answer, error)
+
+	// Vote casts the authenticated caller's up (1) or down (-1) vote for an answer, replacing their previous vote.
+	Vote(ctx context.Context, id uint, value, version int) (*model.VoteSummary, error)
+
+	// Unvote withdraws the authenticated caller's vote for an answer.
+	Unvote(ctx context.Context, id uint, version int) (*model.VoteSummary, error)
+
+	// Update replaces the text of an answer on behalf of the authenticated caller and keeps the previous text in its revision history.
+	// Only the author of the answer, a moderator or an admin can edit it.
+	Update(ctx context.Context, id uint, text string, version int) (*model.Answer, error)
+
+	// GetRevisions retrieves the revision history of an answer, each revision carrying a diff to the version that replaced it.
+	GetRevisions(ctx context.Context, id uint) ([]model.Revision, error)
+}
+
+// SearchService defines the interface for full-text search over questions and answers on service layer.
+//
+// Standart implementation can be obtained via NewSearchService() function.
+type SearchService interface {
+	// Search retrieves questions and answers matching the query ordered by relevance via underlying repository.
+	// Zero limit means DefaultPageSize.
+	Search(ctx context.Context, query string, limit int) (*model.SearchResult, error)
+}
+
+// PurgeService defines the interface for permanent removal of soft-deleted questions and answers,
+// of expired idempotency keys and of full rate limit buckets.
+//
+// Standart implementation can be obtained via NewPurgeService() function.
+type PurgeService interface {
+	// Purge permanently removes questions and answers that have been soft-deleted for longer than the retention period,
+	// idempotency keys that have expired and rate limit buckets that have been refilled.
+	Purge(ctx context.Context) error
+}
+
+// IdempotencyService defines the interface for making retries of requests carrying an idempotency key safe.
+//
+// Standart implementation can be obtained via NewIdempotencyService() function.
+type IdempotencyService interface {
+	// Begin claims the key for a request of the authenticated caller, requestHash identifying the request.
+	// It returns the record of an earlier completed request with the same key and hash, whose response is to be replayed,
+	// or nil if the request is the first one and has to be processed.
+	// The key fails with ErrIdempotencyKeyReused if it was used with a different request
+	// and with ErrIdempotencyKeyInProgress while the earlier request is still being processed.
+	Begin(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, error)
+
+	// Complete stores the response to the request whose key was claimed by Begin, so that retries get it replayed until the key expires.
+	Complete(ctx context.Context, key string, statusCode int, headers map[string]string, body []byte) error
+
+	// Release frees the key claimed by Begin without storing a response, so that the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// RateLimitService defines the interface for limiting the rate of requests made by clients.
+//
+// Standart implementation can be obtained via NewRateLimitService() function.
+type RateLimitService interface {
+	// Allow admits a request of the client identified by key if its token bucket, replenished according to limit, is not empty.
+	// Keys are expected to identify both the client and what it is limited in, e.g. a route.
+	Allow(ctx context.Context, key string, limit model.RateLimit) (*model.RateLimitStatus, error)
 }
 
-func internalError(err, errClass error) error {
+// HealthService defines the interface for liveness and readiness probes of the API.
+//
+// Standart implementation can be obtained via NewHealthService() function.
+type HealthService interface {
+	// Ready runs the readiness checks and reports whether the API is able to serve requests.
+	Ready(ctx context.Context) *model.HealthReport
+	// Drain marks the API as not ready for good, so that traffic is moved away before the server shuts down.
+	Drain()
+}
+
+func internalError(ctx context.Context, err, errClass error) error {
 	joinedErr := errors.Join(errClass, err)
-	slog.Error("unexpected internal error: " + joinedErr.Error())
+	logging.FromContext(ctx).Error("unexpected internal error", "error", joinedErr)
 	return joinedErr
 }
 
+// currentUserID returns the identity of the authenticated caller stored in ctx.
+func currentUserID(ctx context.Context) (string, error) {
+	userID, ok := auth.SubjectFromContext(ctx)
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+	if !isValidUUID(userID) {
+		return "", ErrInvalidUserID
+	}
+	return userID, nil
+}
+
 // ! Нужно было использовать uuid.UUID из внешней библиотеки, но я подумал об этом слишком поздно
 func isValidUUID(uuid string) bool {
 	uuidRegex := regexp.MustCompile(`^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$`)
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTagLength is the maximum length of a tag name in characters.
+const MaxTagLength = 32
+
+// Semantics of filtering questions by several tags.
+const (
+	// TagMatchAll selects questions labelled with every requested tag.
+	TagMatchAll = "all"
+
+	// TagMatchAny selects questions labelled with at least one of the requested tags.
+	TagMatchAny = "any"
+)
+
+// NormalizeTag trims surrounding whitespace and lowercases a tag name.
+// Returns ErrInvalidTag if the result is empty or longer than MaxTagLength characters.
+func NormalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || utf8.RuneCountInString(name) > MaxTagLength {
+		return "", ErrInvalidTag
+	}
+	return name, nil
+}
+
+// normalizeTags normalizes every tag name and drops duplicates keeping the first occurrence.
+func normalizeTags(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name, err := NormalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ppb03/qna-api/internal/model"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ppb03/qna-api/internal/service")
+
+// traced runs call within a span named name. Errors are recorded on the span,
+// but only internal failures mark it as failed since the rest are answers to invalid requests.
+func traced[T any](ctx context.Context, name string, call func(context.Context) (T, error), attrs ...attribute.KeyValue) (T, error) {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	result, err := call(ctx)
+	if err != nil {
+		span.RecordError(err)
+		if errors.Is(err, ErrRepositoryFailure) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	return result, err
+}
+
+// tracedErr is traced for calls returning only an error.
+func tracedErr(ctx context.Context, name string, call func(context.Context) error, attrs ...attribute.KeyValue) error {
+	_, err := traced(ctx, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	}, attrs...)
+	return err
+}
+
+func questionID(id uint) attribute.KeyValue {
+	return attribute.Int64("qna.question.id", int64(id))
+}
+
+func answerID(id uint) attribute.KeyValue {
+	return attribute.Int64("qna.answer.id", int64(id))
+}
+
+type tracedQuestionService struct {
+	next QuestionService
+}
+
+// NewTracedQuestionService creates QuestionService instance which wraps every call of next in an OpenTelemetry span
+func NewTracedQuestionService(next QuestionService) QuestionService {
+	return &tracedQuestionService{next: next}
+}
+
+func (s *tracedQuestionService) Create(ctx context.Context, text string, tags []string) (*model.Question, error) {
+	return traced(ctx, "QuestionService.Create", func(ctx context.Context) (*model.Question, error) {
+		return s.next.Create(ctx, text, tags)
+	})
+}
+
+func (s *tracedQuestionService) Delete(ctx context.Context, id uint, version int) error {
+	return tracedErr(ctx, "QuestionService.Delete", func(ctx context.Context) error {
+		return s.next.Delete(ctx, id, version)
+	}, questionID(id))
+}
+
+func (s *tracedQuestionService) Restore(ctx context.Context, id uint, version int) (*model.Question, error) {
+	return traced(ctx, "QuestionService.Restore", func(ctx context.Context) (*model.Question, error) {
+		return s.next.Restore(ctx, id, version)
+	}, questionID(id))
+}
+
+func (s *tracedQuestionService) Update(ctx context.Context, id uint, text string, version int) (*model.Question, error) {
+	return traced(ctx, "QuestionService.Update", func(ctx context.Context) (*model.Question, error) {
+		return s.next.Update(ctx, id, text, version)
+	}, questionID(id))
+}
+
+func (s *tracedQuestionService) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	return traced(ctx, "QuestionService.GetRevisions", func(ctx context.Context) ([]model.Revision, error) {
+		return s.next.GetRevisions(ctx, id)
+	}, questionID(id))
+}
+
+func (s *tracedQuestionService) GetAll(ctx context.Context, params QuestionListParams) (*model.QuestionPage, error) {
+	return traced(ctx, "QuestionService.GetAll", func(ctx context.Context) (*model.QuestionPage, error) {
+		return s.next.GetAll(ctx, params)
+	})
+}
+
+func (s *tracedQuestionService) GetTags(ctx context.Context) ([]model.TagCount, error) {
+	return traced(ctx, "QuestionService.GetTags", s.next.GetTags)
+}
+
+func (s *tracedQuestionService) GetByID(ctx context.Context, id uint) (*model.Question, error) {
+	return traced(ctx, "QuestionService.GetByID", func(ctx context.Context) (*model.Question, error) {
+		return s.next.GetByID(ctx, id)
+	}, questionID(id))
+}
+
+func (s *tracedQuestionService) Vote(ctx context.Context, id uint, value, version int) (*model.VoteSummary, error) {
+	return traced(ctx, "QuestionService.Vote", func(ctx context.Context) (*model.VoteSummary, error) {
+		return s.next.Vote(ctx, id, value, version)
+	}, questionID(id))
+}
+
+func (s *tracedQuestionService) Unvote(ctx context.Context, id uint, version int) (*model.VoteSummary, error) {
+	return traced(ctx, "QuestionService.Unvote", func(ctx context.Context) (*model.VoteSummary, error) {
+		return s.next.Unvote(ctx, id, version)
+	}, questionID(id))
+}
+
+func (s *tracedQuestionService) Accept(ctx context.Context, id, answer uint, version int) (*model.Question, error) {
+	return traced(ctx, "QuestionService.Accept", func(ctx context.Context) (*model.Question, error) {
+		return s.next.Accept(ctx, id, answer, version)
+	}, questionID(id), answerID(answer))
+}
+
+type tracedAnswerService struct {
+	next AnswerService
+}
+
+// NewTracedAnswerService creates AnswerService instance which wraps every call of next in an OpenTelemetry span
+func NewTracedAnswerService(next AnswerService) AnswerService {
+	return &tracedAnswerService{next: next}
+}
+
+func (s *tracedAnswerService) Create(ctx context.Context, id uint, text string) (*model.Answer, error) {
+	return traced(ctx, "AnswerService.Create", func(ctx context.Context) (*model.Answer, error) {
+		return s.next.Create(ctx, id, text)
+	}, questionID(id))
+}
+
+func (s *tracedAnswerService) Delete(ctx context.Context, id uint, version int) error {
+	return tracedErr(ctx, "AnswerService.Delete", func(ctx context.Context) error {
+		return s.next.Delete(ctx, id, version)
+	}, answerID(id))
+}
+
+func (s *tracedAnswerService) Restore(ctx context.Context, id uint, version int) (*model.Answer, error) {
+	return traced(ctx, "AnswerService.Restore", func(ctx context.Context) (*model.Answer, error) {
+		return s.next.Restore(ctx, id, version)
+	}, answerID(id))
+}
+
+func (s *tracedAnswerService) GetByID(ctx context.Context, id uint) (*model.Answer, error) {
+	return traced(ctx, "AnswerService.GetByID", func(ctx context.Context) (*model.Answer, error) {
+		return s.next.GetByID(ctx, id)
+	}, answerID(id))
+}
+
+func (s *tracedAnswerService) Vote(ctx context.Context, id uint, value, version int) (*model.VoteSummary, error) {
+	return traced(ctx, "AnswerService.Vote", func(ctx context.Context) (*model.VoteSummary, error) {
+		return s.next.Vote(ctx, id, value, version)
+	}, answerID(id))
+}
+
+func (s *tracedAnswerService) Unvote(ctx context.Context, id uint, version int) (*model.VoteSummary, error) {
+	return traced(ctx, "AnswerService.Unvote", func(ctx context.Context) (*model.VoteSummary, error) {
+		return s.next.Unvote(ctx, id, version)
+	}, answerID(id))
+}
+
+func (s *tracedAnswerService) Update(ctx context.Context, id uint, text string, version int) (*model.Answer, error) {
+	return traced(ctx, "AnswerService.Update", func(ctx context.Context) (*model.Answer, error) {
+		return s.next.Update(ctx, id, text, version)
+	}, answerID(id))
+}
+
+func (s *tracedAnswerService) GetRevisions(ctx context.Context, id uint) ([]model.Revision, error) {
+	return traced(ctx, "AnswerService.GetRevisions", func(ctx context.Context) ([]model.Revision, error) {
+		return s.next.GetRevisions(ctx, id)
+	}, answerID(id))
+}
+
+type tracedSearchService struct {
+	next SearchService
+}
+
+// NewTracedSearchService creates SearchService instance which wraps every call of next in an OpenTelemetry span
+func NewTracedSearchService(next SearchService) SearchService {
+	return &tracedSearchService{next: next}
+}
+
+func (s *tracedSearchService) Search(ctx context.Context, query string, limit int) (*model.SearchResult, error) {
+	return traced(ctx, "SearchService.Search", func(ctx context.Context) (*model.SearchResult, error) {
+		return s.next.Search(ctx, query, limit)
+	}, attribute.Int("qna.search.limit", limit))
+}
+
+type tracedPurgeService struct {
+	next PurgeService
+}
+
+// NewTracedPurgeService creates PurgeService instance which wraps every call of next in an OpenTelemetry span
+func NewTracedPurgeService(next PurgeService) PurgeService {
+	return &tracedPurgeService{next: next}
+}
+
+func (s *tracedPurgeService) Purge(ctx context.Context) error {
+	return tracedErr(ctx, "PurgeService.Purge", s.next.Purge)
+}
+
+type tracedIdempotencyService struct {
+	next IdempotencyService
+}
+
+// NewTracedIdempotencyService creates IdempotencyService instance which wraps every call of next in an OpenTelemetry span
+func NewTracedIdempotencyService(next IdempotencyService) IdempotencyService {
+	return &tracedIdempotencyService{next: next}
+}
+
+func (s *tracedIdempotencyService) Begin(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, error) {
+	return traced(ctx, "IdempotencyService.Begin", func(ctx context.Context) (*model.IdempotencyRecord, error) {
+		return s.next.Begin(ctx, key, requestHash)
+	})
+}
+
+func (s *tracedIdempotencyService) Complete(ctx context.Context, key string, statusCode int, headers map[string]string, body []byte) error {
+	return tracedErr(ctx, "IdempotencyService.Complete", func(ctx context.Context) error {
+		return s.next.Complete(ctx, key, statusCode, headers, body)
+	}, attribute.Int("http.response.status_code", statusCode))
+}
+
+func (s *tracedIdempotencyService) Release(ctx context.Context, key string) error {
+	return tracedErr(ctx, "IdempotencyService.Release", func(ctx context.Context) error {
+		return s.next.Release(ctx, key)
+	})
+}
+
+type tracedRateLimitService struct {
+	next RateLimitService
+}
+
+// NewTracedRateLimitService creates RateLimitService instance which wraps every call of next in an OpenTelemetry span
+func NewTracedRateLimitService(next RateLimitService) RateLimitService {
+	return &tracedRateLimitService{next: next}
+}
+
+func (s *tracedRateLimitService) Allow(ctx context.Context, key string, limit model.RateLimit) (*model.RateLimitStatus, error) {
+	return traced(ctx, "RateLimitService.Allow", func(ctx context.Context) (*model.RateLimitStatus, error) {
+		return s.next.Allow(ctx, key, limit)
+	})
+}
@@ -0,0 +1,76 @@
+// Package tracing sets up OpenTelemetry tracing of the application.
+//
+// Spans are created with the global tracer provider and the W3C trace context is propagated
+// with the global propagator, both configured by Setup.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ppb03/qna-api/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+// ServiceName identifies the application in traces unless overridden by OTEL_SERVICE_NAME.
+const ServiceName = "qna-api"
+
+// Setup installs the W3C trace context propagator and, unless exporter is config.TracingNone,
+// a tracer provider sending spans to exporter. The returned function flushes pending spans and must be called on exit.
+//
+// The OTLP exporter sends spans over HTTP and is configured with the standard OTEL_EXPORTER_OTLP_* variables,
+// by default to a collector at localhost:4318. The file exporter writes one JSON object per span to file.
+func Setup(ctx context.Context, exporter, file string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		spanExporter sdktrace.SpanExporter
+		closeFile    = func() error { return nil }
+		err          error
+	)
+	switch exporter {
+	case config.TracingNone:
+		return func(context.Context) error { return nil }, nil
+	case config.TracingOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	case config.TracingStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case config.TracingFile:
+		var f *os.File
+		if f, err = os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+			return nil, err
+		}
+		closeFile = f.Close
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, errors.Join(err, closeFile())
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, errors.Join(err, closeFile())
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(spanExporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		return errors.Join(provider.Shutdown(ctx), closeFile())
+	}, nil
+}
@@ -0,0 +1,7 @@
+-- +goose Up
+CREATE INDEX idx_questions_created_at_id ON questions(created_at, id);
+DROP INDEX idx_questions_created_at;
+
+-- +goose Down
+CREATE INDEX idx_questions_created_at ON questions(created_at);
+DROP INDEX idx_questions_created_at_id;
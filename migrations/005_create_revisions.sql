@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE revisions (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER,
+    answer_id INTEGER,
+    text TEXT NOT NULL,
+    editor_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_revision_question
+        FOREIGN KEY(question_id)
+        REFERENCES questions(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_revision_answer
+        FOREIGN KEY(answer_id)
+        REFERENCES answers(id)
+        ON DELETE CASCADE,
+    CONSTRAINT chk_revision_target
+        CHECK ((question_id IS NULL) <> (answer_id IS NULL))
+);
+
+CREATE INDEX idx_revisions_question_id ON revisions(question_id);
+CREATE INDEX idx_revisions_answer_id ON revisions(answer_id);
+
+-- +goose Down
+DROP TABLE revisions;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE questions ADD COLUMN author_id VARCHAR(255);
+
+CREATE INDEX idx_questions_author_id ON questions(author_id);
+
+-- +goose Down
+DROP INDEX idx_questions_author_id;
+
+ALTER TABLE questions DROP COLUMN author_id;
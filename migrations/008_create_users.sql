@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE users (
+    id VARCHAR(255) PRIMARY KEY,
+    role VARCHAR(32) NOT NULL DEFAULT 'user',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT chk_user_role
+        CHECK (role IN ('user', 'moderator', 'admin'))
+);
+
+-- +goose Down
+DROP TABLE users;
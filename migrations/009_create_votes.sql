@@ -0,0 +1,40 @@
+-- +goose Up
+ALTER TABLE questions ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE answers ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE votes (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    question_id INTEGER,
+    answer_id INTEGER,
+    value SMALLINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_vote_question
+        FOREIGN KEY(question_id)
+        REFERENCES questions(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_vote_answer
+        FOREIGN KEY(answer_id)
+        REFERENCES answers(id)
+        ON DELETE CASCADE,
+    CONSTRAINT chk_vote_target
+        CHECK ((question_id IS NULL) <> (answer_id IS NULL)),
+    CONSTRAINT chk_vote_value
+        CHECK (value IN (-1, 1)),
+    CONSTRAINT uq_vote_user_question
+        UNIQUE (user_id, question_id),
+    CONSTRAINT uq_vote_user_answer
+        UNIQUE (user_id, answer_id)
+);
+
+CREATE INDEX idx_votes_question_id ON votes(question_id);
+CREATE INDEX idx_votes_answer_id ON votes(answer_id);
+CREATE INDEX idx_answers_question_id_score ON answers(question_id, score DESC);
+
+-- +goose Down
+DROP INDEX idx_answers_question_id_score;
+DROP TABLE votes;
+
+ALTER TABLE answers DROP COLUMN score;
+ALTER TABLE questions DROP COLUMN score;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE questions ADD COLUMN accepted_answer_id INTEGER;
+
+ALTER TABLE questions ADD CONSTRAINT fk_question_accepted_answer
+    FOREIGN KEY(accepted_answer_id)
+    REFERENCES answers(id)
+    ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE questions DROP CONSTRAINT fk_question_accepted_answer;
+
+ALTER TABLE questions DROP COLUMN accepted_answer_id;
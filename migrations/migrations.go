@@ -0,0 +1,57 @@
+// Package migrations embeds the goose migrations of the database schema into the binary.
+// The files in this directory are written for PostgreSQL, the ones in sqlite/ are their SQLite counterpart.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/ppb03/qna-api/internal/config"
+
+	"github.com/pressly/goose/v3"
+)
+
+//go:embed *.sql sqlite/*.sql
+var files embed.FS
+
+// ErrSchemaBehind is returned by Check when the database lacks migrations the binary expects.
+var ErrSchemaBehind = errors.New("database schema is behind the expected version")
+
+// NewProvider creates goose provider which applies the embedded migrations of driver
+// (config.DriverPostgres or config.DriverSQLite) to db.
+func NewProvider(db *sql.DB, driver string) (*goose.Provider, error) {
+	dialect, fsys, err := source(driver)
+	if err != nil {
+		return nil, err
+	}
+	return goose.NewProvider(dialect, db, fsys)
+}
+
+// Check reports ErrSchemaBehind if any of the migrations known to provider has not been applied yet.
+// A schema ahead of the binary is accepted, so that an older release keeps working during a rollback.
+func Check(ctx context.Context, provider *goose.Provider) error {
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil {
+		return err
+	}
+	if current < target {
+		return fmt.Errorf("%w: version %d, expected %d", ErrSchemaBehind, current, target)
+	}
+	return nil
+}
+
+func source(driver string) (goose.Dialect, fs.FS, error) {
+	switch driver {
+	case config.DriverPostgres:
+		return goose.DialectPostgres, files, nil
+	case config.DriverSQLite:
+		fsys, err := fs.Sub(files, "sqlite")
+		return goose.DialectSQLite3, fsys, err
+	default:
+		return "", nil, fmt.Errorf("no migrations for database driver %q", driver)
+	}
+}
@@ -0,0 +1,140 @@
+-- +goose Up
+-- SQLite counterpart of the PostgreSQL migrations in the parent directory, without the full-text search columns.
+-- Foreign keys are enforced only on connections opened with PRAGMA foreign_keys = ON.
+CREATE TABLE questions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    author_id VARCHAR(255),
+    text TEXT NOT NULL,
+    score INTEGER NOT NULL DEFAULT 0,
+    accepted_answer_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+
+    CONSTRAINT fk_question_accepted_answer
+        FOREIGN KEY(accepted_answer_id)
+        REFERENCES answers(id)
+        ON DELETE SET NULL
+);
+
+CREATE TABLE answers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    question_id INTEGER NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    score INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+
+    CONSTRAINT fk_question
+        FOREIGN KEY(question_id)
+        REFERENCES questions(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_questions_created_at_id ON questions(created_at, id);
+CREATE INDEX idx_questions_author_id ON questions(author_id);
+CREATE INDEX idx_questions_deleted_at ON questions(deleted_at);
+CREATE INDEX idx_answers_question_id ON answers(question_id);
+CREATE INDEX idx_answers_user_id ON answers(user_id);
+CREATE INDEX idx_answers_created_at ON answers(created_at);
+CREATE INDEX idx_answers_deleted_at ON answers(deleted_at);
+CREATE INDEX idx_answers_question_id_score ON answers(question_id, score DESC);
+
+CREATE TABLE revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    question_id INTEGER,
+    answer_id INTEGER,
+    text TEXT NOT NULL,
+    editor_id VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_revision_question
+        FOREIGN KEY(question_id)
+        REFERENCES questions(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_revision_answer
+        FOREIGN KEY(answer_id)
+        REFERENCES answers(id)
+        ON DELETE CASCADE,
+    CONSTRAINT chk_revision_target
+        CHECK ((question_id IS NULL) <> (answer_id IS NULL))
+);
+
+CREATE INDEX idx_revisions_question_id ON revisions(question_id);
+CREATE INDEX idx_revisions_answer_id ON revisions(answer_id);
+
+CREATE TABLE users (
+    id VARCHAR(255) PRIMARY KEY,
+    role VARCHAR(32) NOT NULL DEFAULT 'user',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_user_role
+        CHECK (role IN ('user', 'moderator', 'admin'))
+);
+
+CREATE TABLE votes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id VARCHAR(255) NOT NULL,
+    question_id INTEGER,
+    answer_id INTEGER,
+    value SMALLINT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_vote_question
+        FOREIGN KEY(question_id)
+        REFERENCES questions(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_vote_answer
+        FOREIGN KEY(answer_id)
+        REFERENCES answers(id)
+        ON DELETE CASCADE,
+    CONSTRAINT chk_vote_target
+        CHECK ((question_id IS NULL) <> (answer_id IS NULL)),
+    CONSTRAINT chk_vote_value
+        CHECK (value IN (-1, 1)),
+    CONSTRAINT uq_vote_user_question
+        UNIQUE (user_id, question_id),
+    CONSTRAINT uq_vote_user_answer
+        UNIQUE (user_id, answer_id)
+);
+
+CREATE INDEX idx_votes_question_id ON votes(question_id);
+CREATE INDEX idx_votes_answer_id ON votes(answer_id);
+
+CREATE TABLE tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(32) NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE question_tags (
+    question_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+
+    PRIMARY KEY (question_id, tag_id),
+    CONSTRAINT fk_question_tag_question
+        FOREIGN KEY(question_id)
+        REFERENCES questions(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_question_tag_tag
+        FOREIGN KEY(tag_id)
+        REFERENCES tags(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_question_tags_tag_id ON question_tags(tag_id);
+
+-- +goose Down
+DROP TABLE question_tags;
+
+DROP TABLE tags;
+
+DROP TABLE votes;
+
+DROP TABLE users;
+
+DROP TABLE revisions;
+
+DROP TABLE answers;
+
+DROP TABLE questions;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE idempotency_records ADD COLUMN headers TEXT;
+
+-- +goose Down
+ALTER TABLE idempotency_records DROP COLUMN headers;